@@ -3,64 +3,60 @@ package main
 import (
 	"crypto/rsa"
 	"crypto/x509"
-	"github.com/go-acme/lego/v4/challenge/http01"
-	"github.com/go-acme/lego/v4/lego"
 	"ssl/certs"
+	"ssl/chain"
 	"ssl/config"
-	"ssl/converters"
-	"ssl/legoadapter"
-	"ssl/storage"
-	"ssl/storage/memory"
+	"ssl/hooks"
+	loglib "ssl/logger"
 	"ssl/validations"
-	"strconv"
+	"strings"
 	"time"
 )
 
-func app(config config.ConfigInterface) (err error) {
+type appOptions struct {
+	// force renews certificate even if current one is valid
+	force bool
+	// dryRun only logs hooks instead of running them
+	dryRun bool
+}
+
+func app(config config.ConfigInterface, options appOptions) (err error) {
 	bundleManager, err := GenerateMultiBundleManagerFromFormatsSlice[*rsa.PrivateKey](config.GetSaveFormats())
 	if err != nil {
 		return
 	}
 
+	defer recordCertificateMetrics(bundleManager)
+
 	err = bundleManager.Sync()
 	if err != nil {
 		return
 	}
 
+	defer refreshOCSPStaples(config, bundleManager, options.dryRun)
+
 	certKey, certificateChain, err := bundleManager.bundleManagers[0].Get()
 	if err != nil {
 		return
 	}
 
-	certificateExpireDuration := time.Duration(config.GetCertDaysLeftMin()) * 24 * time.Hour
-
-	if err = validations.GetCertificateBundleValidationError(certKey, certificateChain, config.GetDomains(), certificateExpireDuration); err != nil {
-		logger.Error(err)
-
-		certKey, certificateChain, err = getNewCertificateBundle(
-			config.GetAccountKeyFilename(),
-			config.GetKeyLength(),
-			config.GetEmail(),
-			config.GetDomains(),
-			config.GetPort(),
-			config.GetUseStaging(),
-		)
-		if err != nil {
-			return err
-		}
-
-		// TODO: order certificates in chain so cert is first, later trust chain in child-to-parent order
-		err = bundleManager.Set(certKey, certificateChain)
-		if err != nil {
-			return err
-		}
+	err = validations.GetCertificateBundleValidationError(certKey, certificateChain, config.GetDomains(), getRenewalThreshold(config))
+	if err == nil && options.dryRun {
+		// dry run does not contact OCSP responders or CRL distribution points
+		logger.Infof(`dry run: revocation status would be checked`)
+	} else if err == nil {
+		err = getRevocationError(config, certificateChain)
+	}
 
-		err = validations.GetCertificateBundleValidationError(certKey, certificateChain, config.GetDomains(), certificateExpireDuration)
+	if err != nil || options.force {
 		if err != nil {
-			logger.Errorf(`retrieved certs are invalid: %s`, err.Error())
+			logger.With(loglib.Fields{`reason`: validations.GetErrorReason(err)}).Warnf(`certificate bundle is invalid, renewing: %s`, err)
+			appMetrics.validationFailures.Inc(validations.GetErrorReason(err))
+		} else {
+			logger.Infof(`certificate renewal is forced`)
 		}
 
-		return nil
+		return renew(config, bundleManager, certificateChain, options)
 	} else {
 		logger.Infof(`certificate bundle is ok`)
 		return NoChangeError
@@ -68,103 +64,107 @@ func app(config config.ConfigInterface) (err error) {
 
 }
 
-func getNewCertificateBundle(accountKeyFilename string, keyLength uint16, email string, domains []string, port int, useStagingCA bool) (key *rsa.PrivateKey, certificates []*x509.Certificate, err error) {
-	accountKey, err := getOrGenerateAccountKey(accountKeyFilename, keyLength)
+// renew runs preRenew hooks, issues and saves new certificate, then runs deploy and postRenew hooks.
+// Dry run stops before the order and only logs the remaining steps.
+// Orders exceeding local rate limits are refused unless forced. Failed preRenew hook aborts renewal.
+// postRenew hooks are run even if issuance fails, then onFailure hooks are run and notifications are sent.
+// Configured endpoints are verified to serve the new certificate at the end.
+func renew(config config.ConfigInterface, bundleManager *MultiBundleManager[*rsa.PrivateKey], oldCertificateChain []*x509.Certificate, options appOptions) (err error) {
+	err = checkRateLimits(config, options.force)
 	if err != nil {
 		return
 	}
 
-	client, err := getConnectedClient(accountKey, email, useStagingCA)
-	if err != nil {
-		return
-	}
+	variables := getHookVariables(config, oldCertificateChain)
 
-	key, err = certs.GeneratePrivateKey(keyLength)
+	err = runHooks(hooks.EventPreRenew, config.GetPreRenewHooks(), variables, options.dryRun)
 	if err != nil {
+		handleRenewalFailure(config, variables, oldCertificateChain, err, options.dryRun)
 		return
 	}
 
-	certificates, err = getCertificates(client, key, domains, port)
+	if options.dryRun {
+		return planRenewal(config, bundleManager, variables)
+	}
 
-	return
-}
+	started := time.Now()
+	appMetrics.renewalLastAttempt.Set(float64(started.Unix()))
 
-func getOrGenerateAccountKey(accountKeyFilename string, keyLength uint16) (key *rsa.PrivateKey, err error) {
-	mgr, err := NewPrivateKeyManager[*rsa.PrivateKey](accountKeyFilename, 0600)
-	if err != nil {
-		return
+	certKey, certificateChain, attempts, err := obtainCertificateBundle(config)
+	recordOrders(config, attempts)
+	if err == nil {
+		certificateChain, err = chain.BuildForPublicKey(certKey.Public(), certificateChain)
+	}
+	if err == nil {
+		err = bundleManager.Set(certKey, certificateChain)
 	}
-
-	key, err = mgr.Get()
 	if err != nil {
+		runPostRenewHooksAfterFailure(config, variables, err, options.dryRun)
+		handleRenewalFailure(config, variables, oldCertificateChain, err, options.dryRun)
 		return
 	}
 
-	if key == nil || validations.GetRSAPrivateKeyLengthError(key, int(keyLength)) != nil {
-		key, err = certs.GeneratePrivateKey(keyLength)
-		if err != nil {
-			return
-		}
+	appMetrics.renewalLastSuccess.Set(float64(time.Now().Unix()))
 
-		err = mgr.Set(key)
-		if err != nil {
-			return
-		}
+	issuerName := attempts[len(attempts)-1].Name
+	recordIssuer(config, certificateChain[0], issuerName)
+
+	validationErr := validations.GetCertificateBundleValidationError(certKey, certificateChain, config.GetDomains(), getRenewalThreshold(config))
+	if validationErr != nil {
+		logger.Errorf(`retrieved certs are invalid: %s`, validationErr.Error())
 	}
 
-	return
-}
+	logger.With(loglib.Fields{
+		`domain`:   strings.Join(config.GetDomains(), `,`),
+		`serial`:   getCertificateChainSerial(certificateChain),
+		`issuer`:   issuerName,
+		`duration`: time.Since(started).Round(time.Millisecond),
+	}).Infof(`certificate renewed`)
 
-func getConnectedClient(accountKey *rsa.PrivateKey, email string, useStagingCA bool) (client *lego.Client, err error) {
-	user := legoadapter.GenerateLegoUser(accountKey, email)
+	notifyRenewalSuccess(config, certificateChain, options.dryRun)
 
-	client, err = legoadapter.GetLegoClient(user, useStagingCA)
-	if err != nil {
-		return
-	}
+	variables[`SSL_NEW_SERIAL`] = getCertificateChainSerial(certificateChain)
 
-	resource, err := legoadapter.LoginOrRegisterIfNotExists(client)
+	err = runHooks(hooks.EventDeploy, config.GetDeployHooks(), variables, options.dryRun)
+	postRenewErr := runHooks(hooks.EventPostRenew, config.GetPostRenewHooks(), variables, options.dryRun)
+	if err == nil {
+		err = postRenewErr
+	}
 	if err != nil {
 		return
 	}
-	user.Registration = resource
 
-	return
+	return verifyEndpoints(config, bundleManager, variables, options.dryRun)
 }
 
-func getCertificates(client *lego.Client, key *rsa.PrivateKey, domains []string, port int) (certificates []*x509.Certificate, err error) {
-	err = client.Challenge.SetHTTP01Provider(http01.NewProviderServer(``, strconv.Itoa(port)))
-	if err != nil {
-		return
-	}
-
-	certificateBytes, err := legoadapter.RequestCertificateBytesForDomains(client, domains, key)
-	if err != nil {
-		return
+func getRenewalThreshold(config config.ConfigInterface) validations.RenewalThreshold {
+	return validations.RenewalThreshold{
+		Before: config.GetRenewBefore(),
+		At:     config.GetRenewAt(),
 	}
+}
 
-	byteStore := memory.NewByteMemory()
-	_ = byteStore.Save(certificateBytes)
-
-	multiByteStore, err := storage.NewByteSingleFileAdapter(byteStore)
+func getOrGenerateAccountKey(accountKeyFilename string, keyLength uint16) (key *rsa.PrivateKey, err error) {
+	mgr, err := NewPrivateKeyManager[*rsa.PrivateKey](accountKeyFilename, 0600)
 	if err != nil {
 		return
 	}
 
-	pemStore, err := storage.NewPemMultibyte(multiByteStore)
+	key, err = mgr.Get()
 	if err != nil {
 		return
 	}
 
-	pemBlocks, err := pemStore.Load()
-	if err != nil {
-		return
-	}
+	if key == nil || validations.GetRSAPrivateKeyLengthError(key, int(keyLength)) != nil {
+		key, err = certs.GeneratePrivateKey(keyLength)
+		if err != nil {
+			return
+		}
 
-	certificates, errs := converters.PEMBlocksToCertificates(pemBlocks)
-	if len(errs) > 0 {
-		err = errs[0]
-		return
+		err = mgr.Set(key)
+		if err != nil {
+			return
+		}
 	}
 
 	return
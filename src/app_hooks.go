@@ -0,0 +1,72 @@
+package main
+
+import (
+	"crypto/x509"
+	"ssl/config"
+	"ssl/hooks"
+	"strings"
+)
+
+// getHookVariables describes certificate to hooks, file paths are taken from the main save format
+func getHookVariables(appConfig config.ConfigInterface, oldCertificateChain []*x509.Certificate) map[string]string {
+	variables := map[string]string{
+		`SSL_CERT_NAME`:  appConfig.GetName(),
+		`SSL_DOMAINS`:    strings.Join(appConfig.GetDomains(), `,`),
+		`SSL_OLD_SERIAL`: getCertificateChainSerial(oldCertificateChain),
+		`SSL_NEW_SERIAL`: ``,
+	}
+
+	saveFormats := appConfig.GetSaveFormats()
+	if len(saveFormats) > 0 {
+		format := saveFormats[0]
+		variables[`SSL_PRIVATE_KEY_FILE`] = format.GetPrivateKeyFilename()
+		variables[`SSL_CERTIFICATE_FILE`] = format.GetCertificateFilename()
+		variables[`SSL_CERTIFICATE_CHAIN_FILE`] = format.GetCertificateChainFilename()
+		variables[`SSL_INTERMEDIATE_FILE`] = format.GetIntermediateFilename()
+		variables[`SSL_PRIVATE_KEY_AND_CERTIFICATE_FILE`] = format.GetPrivateKeyAndCertificateFilename()
+		variables[`SSL_ALL_IN_ONE_FILE`] = format.GetAllInOneFilename()
+	}
+
+	return variables
+}
+
+func getCertificateChainSerial(certificateChain []*x509.Certificate) string {
+	if len(certificateChain) < 1 || certificateChain[0] == nil {
+		return ``
+	}
+
+	return certificateChain[0].SerialNumber.Text(16)
+}
+
+func runHooks(event hooks.Event, hookList []hooks.Hook, variables map[string]string, dryRun bool) error {
+	if dryRun {
+		for _, hook := range hookList {
+			logger.Infof(`dry run: %s hook "%s" would be run`, event, hook.Command)
+		}
+		return nil
+	}
+
+	return hooks.Run(event, hookList, variables)
+}
+
+// runPostRenewHooksAfterFailure starts again what preRenew hooks stopped, hook errors are only logged
+func runPostRenewHooksAfterFailure(appConfig config.ConfigInterface, variables map[string]string, renewalErr error, dryRun bool) {
+	variables[`SSL_ERROR`] = renewalErr.Error()
+
+	err := runHooks(hooks.EventPostRenew, appConfig.GetPostRenewHooks(), variables, dryRun)
+	if err != nil {
+		logger.Error(err)
+	}
+}
+
+// handleRenewalFailure keeps renewal error as the result, failure hook and notification errors are only logged
+func handleRenewalFailure(appConfig config.ConfigInterface, variables map[string]string, oldCertificateChain []*x509.Certificate, renewalErr error, dryRun bool) {
+	variables[`SSL_ERROR`] = renewalErr.Error()
+
+	err := runHooks(hooks.EventFailure, appConfig.GetOnFailureHooks(), variables, dryRun)
+	if err != nil {
+		logger.Error(err)
+	}
+
+	notifyRenewalFailure(appConfig, oldCertificateChain, renewalErr, dryRun)
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"errors"
+	"ssl/certs"
+	"ssl/config"
+	"ssl/issuer"
+	"ssl/legoadapter"
+	"ssl/localca"
+	loglib "ssl/logger"
+	"ssl/storage/file"
+	"ssl/vault"
+	"time"
+)
+
+// newIssuer creates issuer for the config, tests replace it with a fake
+var newIssuer = newConfiguredIssuer
+
+func newConfiguredIssuer(appConfig config.ConfigInterface) (certificateIssuer issuer.Issuer, err error) {
+	if appConfig.GetLocalCAEnabled() {
+		var ca *localca.CA
+		ca, err = getOrCreateLocalCA(appConfig)
+		if err != nil {
+			return
+		}
+		return localca.NewIssuer(ca, appConfig.GetLocalCACertificateValidity()), nil
+	}
+
+	if appConfig.GetVaultEnabled() {
+		var vaultIssuer *vault.Issuer
+		vaultIssuer, err = vault.NewIssuer(appConfig.GetVaultSettings())
+		if err != nil {
+			return
+		}
+		return vaultIssuer, nil
+	}
+
+	providers := appConfig.GetACMEProviders()
+	candidates := make([]issuer.Candidate, 0, len(providers))
+	for _, provider := range providers {
+		provider := provider
+		candidates = append(candidates, issuer.Candidate{
+			Name: provider.Name,
+			New: func() (issuer.Issuer, error) {
+				return newACMEIssuer(appConfig, provider)
+			},
+			Check: func() error {
+				return getRateLimitError(appConfig, provider.Name)
+			},
+		})
+	}
+
+	failover, err := issuer.NewFailover(issuer.FailoverSettings{
+		Candidates:     candidates,
+		ShouldFailover: legoadapter.IsCAError,
+		IssuedBy: func(certificate *x509.Certificate) string {
+			return getRecordedIssuer(appConfig, certificate)
+		},
+	})
+	if err != nil {
+		return
+	}
+
+	return failover, nil
+}
+
+// newACMEIssuer registers account at the provider if needed, orders are preceded by preflight checks unless they are disabled
+func newACMEIssuer(appConfig config.ConfigInterface, provider config.ACMEProvider) (acmeIssuer *legoadapter.Issuer, err error) {
+	accountKey, err := getOrGenerateAccountKey(provider.AccountKeyFilename, appConfig.GetKeyLength())
+	if err != nil {
+		return
+	}
+
+	settings := legoadapter.IssuerSettings{
+		AccountKey:   accountKey,
+		Email:        appConfig.GetEmail(),
+		UseStagingCA: appConfig.GetUseStaging(),
+		DirectoryURL: provider.DirectoryURL,
+		HTTPPort:     appConfig.GetPort(),
+	}
+	if provider.EABKeyID != `` {
+		settings.EAB = &legoadapter.ExternalAccountBinding{KeyID: provider.EABKeyID, HMACKey: provider.EABHMACKey}
+	}
+
+	preflightSettings := getPreflightSettings(appConfig)
+	if preflightSettings != nil {
+		preflightSettings.CAAIdentities = provider.CAAIdentities
+		settings.Preflight = func(accountURI string, identifiers []string) error {
+			return runPreflight(*preflightSettings, accountURI, identifiers)
+		}
+	}
+
+	return legoadapter.NewIssuer(settings)
+}
+
+// usesACMEIssuer tells if CA rate limits apply, other issuers are internal
+func usesACMEIssuer(appConfig config.ConfigInterface) bool {
+	return !appConfig.GetLocalCAEnabled() && !appConfig.GetVaultEnabled()
+}
+
+// attemptsIssuer is implemented by issuers which may place an order at several CAs
+type attemptsIssuer interface {
+	Attempts() []issuer.Attempt
+}
+
+// obtainCertificateBundle generates a new key and obtains certificate for it, orders placed at every CA are returned.
+// ACME issuer falls back to the next configured CA if an order fails on CA side.
+func obtainCertificateBundle(appConfig config.ConfigInterface) (key *rsa.PrivateKey, certificates []*x509.Certificate, attempts []issuer.Attempt, err error) {
+	certificateIssuer, err := newIssuer(appConfig)
+	if err != nil {
+		return
+	}
+
+	key, err = certs.GeneratePrivateKey(appConfig.GetKeyLength())
+	if err != nil {
+		return
+	}
+
+	started := time.Now()
+	certificates, err = certificateIssuer.Obtain(issuer.Request{Identifiers: appConfig.GetDomains(), Key: key})
+	if usesACMEIssuer(appConfig) {
+		appMetrics.observeACMERequest(started, err)
+	}
+
+	if multiIssuer, ok := certificateIssuer.(attemptsIssuer); ok {
+		attempts = multiIssuer.Attempts()
+	} else {
+		attempts = []issuer.Attempt{{Name: certificateIssuer.Name(), Err: err}}
+	}
+
+	return
+}
+
+func loadIssuerRegistry(appConfig config.ConfigInterface) (registry *issuer.Registry, save func() error, err error) {
+	store, err := file.NewByteFile(appConfig.GetIssuerRegistryFilename(), ledgerPermissions)
+	if err != nil {
+		return
+	}
+
+	registry, err = issuer.LoadRegistry(store)
+	if err != nil {
+		err = errors.New(`issuer registry "` + appConfig.GetIssuerRegistryFilename() + `": ` + err.Error())
+		return
+	}
+
+	save = func() error {
+		return registry.Save(store)
+	}
+
+	return
+}
+
+// recordIssuer remembers CA of the certificate, so it is revoked only there
+func recordIssuer(appConfig config.ConfigInterface, certificate *x509.Certificate, issuerName string) {
+	if !usesACMEIssuer(appConfig) {
+		return
+	}
+
+	registry, save, err := loadIssuerRegistry(appConfig)
+	if err == nil {
+		registry.Record(certificate, issuerName, time.Now())
+		err = save()
+	}
+	if err != nil {
+		logger.With(loglib.Fields{`file`: appConfig.GetIssuerRegistryFilename()}).Errorf(`issuer of certificate is not recorded: %s`, err)
+	}
+}
+
+// getRecordedIssuer returns empty string if the certificate was issued before registry existed
+func getRecordedIssuer(appConfig config.ConfigInterface, certificate *x509.Certificate) string {
+	registry, _, err := loadIssuerRegistry(appConfig)
+	if err != nil {
+		logger.Warnf(`issuer of certificate is unknown: %s`, err)
+		return ``
+	}
+	return registry.GetIssuer(certificate)
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"crypto/rsa"
+	"encoding/pem"
+	"errors"
+	"ssl/certs"
+	"ssl/config"
+	"ssl/converters"
+	"ssl/localca"
+	"time"
+)
+
+const (
+	localCAKeyPermissions         = 0600
+	localCACertificatePermissions = 0644
+)
+
+// getOrCreateLocalCA loads CA from its files, new CA is generated and saved if both files are missing
+func getOrCreateLocalCA(appConfig config.ConfigInterface) (ca *localca.CA, err error) {
+	keyManager, err := NewPrivateKeyManager[*rsa.PrivateKey](appConfig.GetLocalCAKeyFilename(), localCAKeyPermissions)
+	if err != nil {
+		return
+	}
+
+	certificateStore, err := getPemStorageFromFilenameAndPermissions(appConfig.GetLocalCACertificateFilename(), localCACertificatePermissions)
+	if err != nil {
+		return
+	}
+
+	key, err := keyManager.Get()
+	if err != nil {
+		return
+	}
+
+	pemBlocks, err := certificateStore.Load()
+	if err != nil {
+		return
+	}
+
+	if key != nil && len(pemBlocks) > 0 {
+		certificate, err := converters.PEMBlockToCertificate(pemBlocks[0])
+		if err != nil {
+			return nil, err
+		}
+		return localca.Load(key, certificate)
+	}
+
+	if key != nil || len(pemBlocks) > 0 {
+		return nil, errors.New(`local CA key or certificate is missing, remove both files to generate a new CA`)
+	}
+
+	key, err = certs.GeneratePrivateKey(appConfig.GetKeyLength())
+	if err != nil {
+		return
+	}
+
+	ca, err = localca.New(key, appConfig.GetLocalCACommonName(), appConfig.GetLocalCAValidity(), time.Now())
+	if err != nil {
+		return
+	}
+
+	err = keyManager.Set(key)
+	if err != nil {
+		return
+	}
+
+	pemBlock, err := converters.CertificateToPEMBlock(ca.Certificate)
+	if err != nil {
+		return
+	}
+
+	err = certificateStore.Save([]*pem.Block{pemBlock})
+	if err != nil {
+		return
+	}
+
+	logger.Infof(`local CA "%s" generated, install "%s" into trust stores of development machines`,
+		appConfig.GetLocalCACommonName(), appConfig.GetLocalCACertificateFilename())
+
+	return
+}
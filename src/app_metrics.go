@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"ssl/config"
+	"ssl/keytype"
+	"ssl/metrics"
+	"strconv"
+	"time"
+)
+
+const metricsShutdownTimeout = 5 * time.Second
+
+var acmeRequestDurationBuckets = []float64{1, 2, 5, 10, 20, 30, 60, 120, 300}
+
+type applicationMetrics struct {
+	registry            *metrics.Registry
+	certificateNotAfter *metrics.Gauge
+	renewalLastAttempt  *metrics.Gauge
+	renewalLastSuccess  *metrics.Gauge
+	acmeRequests        *metrics.Counter
+	acmeRequestDuration *metrics.Histogram
+	syncMismatches      *metrics.Counter
+	validationFailures  *metrics.Counter
+}
+
+var appMetrics = newApplicationMetrics()
+
+func newApplicationMetrics() *applicationMetrics {
+	registry := metrics.NewRegistry()
+	return &applicationMetrics{
+		registry:            registry,
+		certificateNotAfter: registry.NewGauge(`ssl_certificate_not_after_timestamp_seconds`, `Certificate expiration time.`, `format`, `certificate`, `common_name`),
+		renewalLastAttempt:  registry.NewGauge(`ssl_renewal_last_attempt_timestamp_seconds`, `Time of the last renewal attempt.`),
+		renewalLastSuccess:  registry.NewGauge(`ssl_renewal_last_success_timestamp_seconds`, `Time of the last successful renewal.`),
+		acmeRequests:        registry.NewCounter(`ssl_acme_requests_total`, `ACME certificate requests by outcome.`, `outcome`),
+		acmeRequestDuration: registry.NewHistogram(`ssl_acme_request_duration_seconds`, `ACME certificate request latency by outcome.`, acmeRequestDurationBuckets, `outcome`),
+		syncMismatches:      registry.NewCounter(`ssl_sync_mismatches_total`, `Save formats found out of sync with the main one.`, `format`),
+		validationFailures:  registry.NewCounter(`ssl_validation_failures_total`, `Certificate bundle validation failures by reason.`, `reason`),
+	}
+}
+
+func (m *applicationMetrics) observeACMERequest(started time.Time, err error) {
+	outcome := `success`
+	if err != nil {
+		outcome = `failure`
+	}
+	m.acmeRequests.Inc(outcome)
+	m.acmeRequestDuration.Observe(time.Since(started).Seconds(), outcome)
+}
+
+func recordCertificateMetrics[T keytype.Private](bundleManager *MultiBundleManager[T]) {
+	appMetrics.certificateNotAfter.Reset()
+	for num, mgr := range bundleManager.bundleManagers {
+		_, certificateChain, _ := mgr.Get()
+		for position, certificate := range certificateChain {
+			if certificate == nil {
+				continue
+			}
+			name := `leaf`
+			if position > 0 {
+				name = `intermediate` + strconv.Itoa(position)
+			}
+			appMetrics.certificateNotAfter.Set(float64(certificate.NotAfter.Unix()), strconv.Itoa(num), name, certificate.Subject.CommonName)
+		}
+	}
+}
+
+func writeMetricsTextfile(appConfig config.ConfigInterface) {
+	filename := appConfig.GetMetricsTextfile()
+	if filename == `` {
+		return
+	}
+
+	err := appMetrics.registry.WriteFile(filename)
+	if err != nil {
+		logger.Errorf(`metrics textfile was not written: %s`, err)
+	}
+}
+
+// startMetricsServer returns function stopping the server, it does nothing if listen address is not configured
+func startMetricsServer(appConfig config.ConfigInterface) (stop func()) {
+	listen := appConfig.GetMetricsListen()
+	if listen == `` {
+		return func() {}
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(`/metrics`, appMetrics.registry.Handler())
+	server := &http.Server{Addr: listen, Handler: mux}
+
+	go func() {
+		logger.Infof(`metrics are served at "%s/metrics"`, listen)
+		err := server.ListenAndServe()
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Errorf(`metrics server failed: %s`, err)
+		}
+	}()
+
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), metricsShutdownTimeout)
+		defer cancel()
+		_ = server.Shutdown(ctx)
+	}
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"crypto/x509"
+	"ssl/config"
+	loglib "ssl/logger"
+	"ssl/notify"
+	"ssl/storage/file"
+	"time"
+)
+
+func newRenewalMessage(appConfig config.ConfigInterface, event notify.Event, certificateChain []*x509.Certificate, renewalErr error) *notify.Message {
+	message := &notify.Message{
+		Event:   event,
+		Name:    appConfig.GetName(),
+		Domains: appConfig.GetDomains(),
+		Serial:  getCertificateChainSerial(certificateChain),
+	}
+
+	if len(certificateChain) > 0 && certificateChain[0] != nil {
+		message.NotAfter = certificateChain[0].NotAfter
+		message.DaysLeft = int(time.Until(message.NotAfter).Hours() / 24)
+	}
+
+	if renewalErr != nil {
+		message.Error = renewalErr.Error()
+	}
+
+	return message
+}
+
+// sendNotifications never fails the run, broken notifiers are only logged
+func sendNotifications(appConfig config.ConfigInterface, messages []*notify.Message, dryRun bool) {
+	if dryRun {
+		for _, message := range messages {
+			logger.Infof(`dry run: "%s" notification would be sent`, message.Event)
+		}
+		return
+	}
+
+	dispatcher, err := notify.NewDispatcher(appConfig.GetNotificationSettings())
+	if err != nil {
+		logger.Error(err)
+		return
+	}
+
+	for _, message := range messages {
+		for _, notifyErr := range dispatcher.Notify(message) {
+			logger.Error(notifyErr)
+		}
+	}
+}
+
+// notifyRenewalSuccess also ends the streak of failed renewals
+func notifyRenewalSuccess(appConfig config.ConfigInterface, certificateChain []*x509.Certificate, dryRun bool) {
+	if !dryRun {
+		updateRenewalFailures(appConfig, func(failures *notify.Failures) {
+			failures.Reset()
+		})
+	}
+
+	sendNotifications(appConfig, []*notify.Message{newRenewalMessage(appConfig, notify.EventSuccess, certificateChain, nil)}, dryRun)
+}
+
+// notifyRenewalFailure also sends "expiring" event if current certificate is close to expiration and renewal keeps failing
+func notifyRenewalFailure(appConfig config.ConfigInterface, oldCertificateChain []*x509.Certificate, renewalErr error, dryRun bool) {
+	messages := []*notify.Message{newRenewalMessage(appConfig, notify.EventFailure, oldCertificateChain, renewalErr)}
+
+	failures := &notify.Failures{}
+	if !dryRun {
+		failures = updateRenewalFailures(appConfig, func(failures *notify.Failures) {
+			failures.Record(time.Now())
+		})
+	}
+
+	expiring := newRenewalMessage(appConfig, notify.EventExpiring, oldCertificateChain, renewalErr)
+	if !expiring.NotAfter.IsZero() && expiring.DaysLeft <= appConfig.GetNotificationExpiringDays() {
+		if failures.Count >= appConfig.GetNotificationExpiringAfterFailures() {
+			messages = append(messages, expiring)
+		} else {
+			logger.Infof(`certificate expires in %d days, "%s" is sent if renewal keeps failing`, expiring.DaysLeft, notify.EventExpiring)
+		}
+	}
+
+	sendNotifications(appConfig, messages, dryRun)
+}
+
+// updateRenewalFailures never fails the run, state which can not be loaded starts over
+func updateRenewalFailures(appConfig config.ConfigInterface, update func(failures *notify.Failures)) (failures *notify.Failures) {
+	failures = &notify.Failures{}
+
+	store, err := file.NewByteFile(appConfig.GetRenewalFailuresFilename(), ledgerPermissions)
+	if err == nil {
+		var loaded *notify.Failures
+		loaded, err = notify.LoadFailures(store)
+		if loaded != nil {
+			failures = loaded
+		}
+	}
+	if err != nil {
+		logger.With(loglib.Fields{`file`: appConfig.GetRenewalFailuresFilename()}).Warnf(`renewal failures are counted from scratch: %s`, err)
+	}
+
+	update(failures)
+
+	if store != nil {
+		err = failures.Save(store)
+		if err != nil {
+			logger.With(loglib.Fields{`file`: appConfig.GetRenewalFailuresFilename()}).Errorf(`renewal failures are not saved: %s`, err)
+		}
+	}
+
+	return
+}
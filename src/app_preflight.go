@@ -0,0 +1,46 @@
+package main
+
+import (
+	"ssl/config"
+	loglib "ssl/logger"
+	"ssl/preflight"
+	"strings"
+)
+
+// getPreflightSettings returns nil if preflight checks are disabled
+func getPreflightSettings(appConfig config.ConfigInterface) *preflight.Settings {
+	if !appConfig.GetPreflightEnabled() {
+		return nil
+	}
+
+	settings := appConfig.GetPreflightSettings()
+	return &settings
+}
+
+// runPreflight checks every domain before an order is placed, failed validations count against CA rate limits
+func runPreflight(settings preflight.Settings, accountURI string, domains []string) error {
+	settings.AccountURI = accountURI
+
+	checker, err := preflight.NewChecker(settings)
+	if err != nil {
+		return err
+	}
+
+	results, err := checker.Check(domains)
+	for _, result := range results {
+		if len(result.Problems) > 0 {
+			continue
+		}
+
+		addresses := make([]string, 0, len(result.Addresses))
+		for _, address := range result.Addresses {
+			addresses = append(addresses, address.String())
+		}
+		logger.With(loglib.Fields{
+			`domain`:    result.Domain,
+			`addresses`: strings.Join(addresses, `,`),
+		}).Debugf(`preflight passed`)
+	}
+
+	return err
+}
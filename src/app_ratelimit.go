@@ -0,0 +1,99 @@
+package main
+
+import (
+	"errors"
+	"ssl/config"
+	"ssl/issuer"
+	"ssl/legoadapter"
+	loglib "ssl/logger"
+	"ssl/ratelimit"
+	"ssl/storage/file"
+	"time"
+)
+
+const ledgerPermissions = 0600
+
+func loadRateLimitLedger(appConfig config.ConfigInterface) (ledger *ratelimit.Ledger, save func() error, err error) {
+	store, err := file.NewByteFile(appConfig.GetRateLimitLedgerFilename(), ledgerPermissions)
+	if err != nil {
+		return
+	}
+
+	ledger, err = ratelimit.Load(store)
+	if err != nil {
+		err = errors.New(`rate limit ledger "` + appConfig.GetRateLimitLedgerFilename() + `": ` + err.Error())
+		return
+	}
+
+	save = func() error {
+		return ledger.Save(store)
+	}
+
+	return
+}
+
+// checkRateLimits refuses an order which would exceed CA limits according to the ledger, forced order is only logged
+func checkRateLimits(appConfig config.ConfigInterface, force bool) (err error) {
+	if !usesACMEIssuer(appConfig) {
+		return nil
+	}
+
+	// order can fall back to any provider, so it is refused only if all of them are over limits
+	for i, provider := range appConfig.GetACMEProviders() {
+		providerErr := getRateLimitError(appConfig, provider.Name)
+		if providerErr == nil {
+			return nil
+		}
+		if i == 0 {
+			err = providerErr
+		}
+	}
+
+	if err != nil && force {
+		logger.Warnf(`%s, order is forced anyway`, err)
+		return nil
+	}
+
+	return err
+}
+
+// getRateLimitError returns nil if rate limits are disabled or the CA is within them
+func getRateLimitError(appConfig config.ConfigInterface, caName string) error {
+	if !appConfig.GetRateLimitsEnabled() {
+		return nil
+	}
+
+	ledger, _, err := loadRateLimitLedger(appConfig)
+	if err != nil {
+		return err
+	}
+
+	return ledger.Check(appConfig.GetRateLimits(), caName, appConfig.GetDomains(), time.Now())
+}
+
+// recordOrders adds issued certificate or failed validation of every attempted CA to the ledger.
+// Other failures, e.g. CA outage or refused order, are not counted by CA as failed validations.
+func recordOrders(appConfig config.ConfigInterface, attempts []issuer.Attempt) {
+	if !appConfig.GetRateLimitsEnabled() || !usesACMEIssuer(appConfig) || len(attempts) < 1 {
+		return
+	}
+
+	ledger, save, err := loadRateLimitLedger(appConfig)
+	if err == nil {
+		limits := appConfig.GetRateLimits()
+		for _, attempt := range attempts {
+			kind := ratelimit.KindIssued
+			if attempt.Err != nil {
+				if !legoadapter.IsValidationError(attempt.Err) {
+					continue
+				}
+				kind = ratelimit.KindFailure
+			}
+			ledger.Record(kind, attempt.Name, appConfig.GetDomains(), time.Now(), limits.MaxPeriod())
+		}
+		err = save()
+	}
+	if err != nil {
+		logger.With(loglib.Fields{`file`: appConfig.GetRateLimitLedgerFilename()}).Errorf(`order is not recorded in rate limit ledger: %s`, err)
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"crypto/x509"
+	"errors"
+	"ssl/config"
+	loglib "ssl/logger"
+	"ssl/revocation"
+	"ssl/validations"
+	"time"
+)
+
+// checkRevocation asks OCSP responders (or CRLs) about the leaf, issuer is taken from the stored chain
+func checkRevocation(appConfig config.ConfigInterface, certificateChain []*x509.Certificate) (result *revocation.Result, err error) {
+	if len(certificateChain) < 2 {
+		return &revocation.Result{Status: revocation.StatusUnknown}, errors.New(`issuer certificate is not in the chain`)
+	}
+
+	return revocation.NewChecker(appConfig.GetRevocationCheckTimeout()).Check(certificateChain[0], certificateChain[1])
+}
+
+// getRevocationError returns validation error for revoked certificates only.
+// Unknown status is logged, so unreachable responders do not cause renewals.
+func getRevocationError(appConfig config.ConfigInterface, certificateChain []*x509.Certificate) error {
+	// local CA publishes neither OCSP nor CRL
+	if !appConfig.GetRevocationCheckEnabled() || appConfig.GetLocalCAEnabled() {
+		return nil
+	}
+
+	result, err := checkRevocation(appConfig, certificateChain)
+	fields := loglib.Fields{
+		`serial`: getCertificateChainSerial(certificateChain),
+		`status`: string(result.Status),
+		`source`: result.Source,
+	}
+	if err != nil {
+		logger.With(fields).Warnf(`revocation status check failed: %s`, err)
+		return nil
+	}
+
+	if result.Status != revocation.StatusRevoked {
+		logger.With(fields).Debugf(`certificate revocation status is %s`, result.Status)
+		return nil
+	}
+
+	fields[`revokedAt`] = result.RevokedAt.Format(time.RFC3339)
+	logger.With(fields).Warnf(`certificate is revoked`)
+
+	return &validations.Error{
+		Reason: validations.ReasonRevoked,
+		Err:    errors.New(`certificate was revoked at "` + result.RevokedAt.Format(time.RFC3339) + `" according to ` + result.Source),
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"errors"
+	"os"
+	"ssl/config"
+	loglib "ssl/logger"
+	"ssl/revocation"
+	"ssl/storage"
+	"ssl/storage/file"
+	"time"
+)
+
+// refreshOCSPStaples keeps DER OCSP responses of save formats fresh, regardless of certificate renewal.
+// Failures are logged only, stale staple is better handled by the server than a failed run.
+// Dry run does not contact OCSP responder.
+func refreshOCSPStaples(appConfig config.ConfigInterface, bundleManager *MultiBundleManager[*rsa.PrivateKey], dryRun bool) {
+	// local CA has no OCSP responder
+	if appConfig.GetLocalCAEnabled() {
+		return
+	}
+
+	var certificateChain []*x509.Certificate
+	for _, format := range appConfig.GetSaveFormats() {
+		filename := format.GetOCSPStapleFilename()
+		if filename == `` {
+			continue
+		}
+
+		if dryRun {
+			logger.Infof(`dry run: ocsp staple "%s" would be refreshed if it is stale`, filename)
+			continue
+		}
+
+		if certificateChain == nil {
+			var err error
+			_, certificateChain, err = bundleManager.Get()
+			if err == nil && len(certificateChain) < 2 {
+				err = errors.New(`issuer certificate is not in the chain`)
+			}
+			if err != nil {
+				logger.Errorf(`ocsp staples are not refreshed: %s`, err)
+				return
+			}
+		}
+
+		err := refreshOCSPStaple(appConfig, filename, format.GetOCSPStaplePermissions(), certificateChain)
+		if err != nil {
+			logger.With(loglib.Fields{`file`: filename}).Errorf(`ocsp staple is not refreshed: %s`, err)
+		}
+	}
+}
+
+func refreshOCSPStaple(appConfig config.ConfigInterface, filename string, permissions os.FileMode, certificateChain []*x509.Certificate) (err error) {
+	store, err := file.NewByteFile(filename, permissions)
+	if err != nil {
+		return
+	}
+
+	certificate, issuer := certificateChain[0], certificateChain[1]
+	fields := loglib.Fields{`file`: filename, `serial`: getCertificateChainSerial(certificateChain)}
+
+	data, err := store.Load()
+	if err != nil && !errors.Is(err, storage.EmptyNode) {
+		return
+	}
+
+	stapleErr := revocation.GetStapleError(data, certificate, issuer, time.Now())
+	if stapleErr == nil {
+		logger.With(fields).Debugf(`ocsp staple is fresh`)
+		return nil
+	}
+
+	result, err := revocation.NewChecker(appConfig.GetRevocationCheckTimeout()).FetchOCSP(certificate, issuer)
+	if err != nil {
+		return
+	}
+
+	if result.Status != revocation.StatusGood {
+		return errors.New(`responder returned "` + string(result.Status) + `" status`)
+	}
+
+	err = store.Save(result.Response)
+	if err != nil {
+		return
+	}
+
+	logger.With(fields).Infof(`ocsp staple refreshed (%s), valid until %s`, stapleErr, result.NextUpdate.Format(time.RFC3339))
+
+	return
+}
@@ -0,0 +1,438 @@
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"errors"
+	"github.com/go-acme/lego/v4/acme"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"ssl/config"
+	"ssl/issuer"
+	"ssl/localca"
+	"ssl/preflight"
+	"ssl/ratelimit"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeIssuer signs certificates with an in-memory CA and records requests
+type fakeIssuer struct {
+	name     string
+	ca       *localca.CA
+	err      error
+	requests []issuer.Request
+}
+
+func newFakeIssuer(t *testing.T) *fakeIssuer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ca, err := localca.New(key, `fake CA`, 24*time.Hour, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &fakeIssuer{ca: ca}
+}
+
+// Name matches the default ACME provider unless set, so orders are recorded in its rate limit ledger
+func (f *fakeIssuer) Name() string {
+	if f.name != `` {
+		return f.name
+	}
+	return `staging`
+}
+
+func (f *fakeIssuer) Obtain(request issuer.Request) ([]*x509.Certificate, error) {
+	f.requests = append(f.requests, request)
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	certificate, err := f.ca.Issue(request.Key.Public(), request.Identifiers, 12*time.Hour, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	return []*x509.Certificate{certificate, f.ca.Certificate}, nil
+}
+
+func (f *fakeIssuer) Revoke(certificate *x509.Certificate, reason int) error {
+	return issuer.ErrNotSupported
+}
+
+func (f *fakeIssuer) RenewalInfo(certificate *x509.Certificate, issuerCertificate *x509.Certificate) (*issuer.RenewalInfo, error) {
+	return nil, issuer.ErrNotSupported
+}
+
+// setUpApp writes config into a temporary folder and injects the fake issuer, "{folder}" in extra is replaced by the folder
+func setUpApp(t *testing.T, fake *fakeIssuer, extra string) (appConfig config.ConfigInterface, folder string) {
+	folder = t.TempDir()
+
+	content := `{
+		"env": "dev",
+		"email": "admin@example.com",
+		"domains": ["example.com", "www.example.com"],
+		"port": 5002,
+		"keyLength": 2048,
+		"renewBefore": "1h",
+		"accountKeyFilename": "` + filepath.Join(folder, `account.key`) + `",
+		"saveFormats": [{"folder": "` + folder + `", "privateKey": "key.pem", "certificate": "cert.pem", "certificateChain": "chain.pem"}],
+		"revocation": {"enabled": false},
+		"preflight": {"enabled": false},
+		"rateLimits": {"duplicateCertificates": {"count": 1, "period": "1h"}}` + extra + `
+	}`
+	content = strings.ReplaceAll(content, `{folder}`, folder)
+	err := os.WriteFile(filepath.Join(folder, `config.json`), []byte(content), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	appConfig, errs := config.Initialize(`APP_TEST_ENV`, `APP_TEST_CONFIG_FOLDER`, config.Overrides{ConfigFolder: folder})
+	if len(errs) > 0 {
+		t.Fatal(errs)
+	}
+
+	previous := newIssuer
+	newIssuer = func(config.ConfigInterface) (issuer.Issuer, error) {
+		return fake, nil
+	}
+	t.Cleanup(func() {
+		newIssuer = previous
+	})
+
+	return
+}
+
+func TestAppIssuesAndKeepsCertificate(t *testing.T) {
+	fake := newFakeIssuer(t)
+	appConfig, folder := setUpApp(t, fake, ``)
+
+	err := app(appConfig, appOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fake.requests) != 1 || strings.Join(fake.requests[0].Identifiers, `,`) != `example.com,www.example.com` {
+		t.Fatal(`one order for configured domains expected`)
+	}
+
+	certificates, err := os.ReadFile(filepath.Join(folder, `chain.pem`))
+	if err != nil || strings.Count(string(certificates), `BEGIN CERTIFICATE`) != 2 {
+		t.Fatal(`leaf and issuer should be saved to the chain file`)
+	}
+
+	err = app(appConfig, appOptions{})
+	if !errors.Is(err, NoChangeError) {
+		t.Fatal(`valid certificate should be kept, got: `, err)
+	}
+	if len(fake.requests) != 1 {
+		t.Fatal(`no order expected for valid certificate`)
+	}
+}
+
+func TestAppRenewalFailure(t *testing.T) {
+	fake := newFakeIssuer(t)
+	fake.err = errors.New(`order rejected`)
+	appConfig, folder := setUpApp(t, fake, `,
+		"hooks": {"onFailure": [{"command": "printf '%s' \"$SSL_ERROR\" > {folder}/error.txt"}]}`)
+
+	err := app(appConfig, appOptions{})
+	if err == nil || !strings.Contains(err.Error(), `order rejected`) {
+		t.Fatal(`issuer error expected, got: `, err)
+	}
+
+	hookOutput, err := os.ReadFile(filepath.Join(folder, `error.txt`))
+	if err != nil || string(hookOutput) != `order rejected` {
+		t.Fatal(`onFailure hook should get the error`)
+	}
+}
+
+func TestAppRateLimits(t *testing.T) {
+	fake := newFakeIssuer(t)
+	appConfig, _ := setUpApp(t, fake, ``)
+
+	err := app(appConfig, appOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = app(appConfig, appOptions{force: true})
+	if err != nil {
+		t.Fatal(`forced order should ignore local rate limits: `, err)
+	}
+
+	// forced orders are recorded too, so the limit is still reached
+	err = renew(appConfig, mustGetBundleManager(t, appConfig), nil, appOptions{})
+	var limitErr *ratelimit.Error
+	if !errors.As(err, &limitErr) {
+		t.Fatal(`rate limit error expected, got: `, err)
+	}
+	if len(fake.requests) != 2 {
+		t.Fatal(`refused order should not reach issuer`)
+	}
+}
+
+func TestAppFailover(t *testing.T) {
+	errValidation := &acme.ProblemDetails{Type: `urn:ietf:params:acme:error:unauthorized`, HTTPStatus: http.StatusForbidden}
+	primary := newFakeIssuer(t)
+	primary.name = `primary`
+	primary.err = errValidation
+	secondary := newFakeIssuer(t)
+	secondary.name = `secondary`
+
+	appConfig, _ := setUpApp(t, nil, `,
+		"rateLimits": {"duplicateCertificates": {"count": 2, "period": "1h"}, "failedValidations": {"count": 1, "period": "1h"}},
+		"acmeProviders": [
+			{"name": "primary", "directoryUrl": "https://primary.example/directory", "accountKeyFilename": "{folder}/primary.key"},
+			{"name": "secondary", "directoryUrl": "https://secondary.example/directory", "accountKeyFilename": "{folder}/secondary.key"}
+		]`)
+	newIssuer = func(appConfig config.ConfigInterface) (issuer.Issuer, error) {
+		candidates := make([]issuer.Candidate, 0)
+		for _, fake := range []*fakeIssuer{primary, secondary} {
+			fake := fake
+			candidates = append(candidates, issuer.Candidate{
+				Name: fake.name,
+				New: func() (issuer.Issuer, error) {
+					return fake, nil
+				},
+				Check: func() error {
+					return getRateLimitError(appConfig, fake.name)
+				},
+			})
+		}
+		return issuer.NewFailover(issuer.FailoverSettings{
+			Candidates: candidates,
+			ShouldFailover: func(err error) bool {
+				return errors.Is(err, errValidation)
+			},
+		})
+	}
+
+	err := app(appConfig, appOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(primary.requests) != 1 || len(secondary.requests) != 1 {
+		t.Fatal(`secondary CA should issue after primary failure`)
+	}
+
+	ledger, _, err := loadRateLimitLedger(appConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ledger.Entries) != 2 || ledger.Entries[0].CA != `primary` || ledger.Entries[0].Kind != ratelimit.KindFailure || ledger.Entries[1].CA != `secondary` {
+		t.Fatal(`every attempted CA should be recorded in the ledger`)
+	}
+
+	certificate, err := mustGetBundleManager(t, appConfig).GetCertificate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if getRecordedIssuer(appConfig, certificate) != `secondary` {
+		t.Fatal(`issuing CA should be recorded for revocation`)
+	}
+
+	// primary is over its failed validations limit now
+	err = renew(appConfig, mustGetBundleManager(t, appConfig), nil, appOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(primary.requests) != 1 || len(secondary.requests) != 2 {
+		t.Fatal(`CA over its local rate limit should be skipped`)
+	}
+}
+
+func TestRecordOrders_OnlyValidationFailures(t *testing.T) {
+	appConfig, _ := setUpApp(t, newFakeIssuer(t), ``)
+
+	recordOrders(appConfig, []issuer.Attempt{
+		{Name: `staging`, Err: errors.New(`503 :: POST :: https://ca.example/new-order :: unexpected response`)},
+		{Name: `staging`, Err: &acme.ProblemDetails{Type: `urn:ietf:params:acme:error:rateLimited`, HTTPStatus: http.StatusTooManyRequests}},
+		{Name: `staging`, Err: &preflight.Error{}},
+		{Name: `staging`, Err: errors.New(`account key is not readable`)},
+		{Name: `staging`, Err: &acme.ProblemDetails{Type: `urn:ietf:params:acme:error:dns`, HTTPStatus: http.StatusBadRequest}},
+	})
+
+	ledger, _, err := loadRateLimitLedger(appConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ledger.Entries) != 1 || ledger.Entries[0].Kind != ratelimit.KindFailure {
+		t.Fatal(`only failed validation should be recorded as failure`)
+	}
+}
+
+func TestAppExpiringAfterRepeatedFailures(t *testing.T) {
+	var expiring int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&expiring, 1)
+	}))
+	t.Cleanup(server.Close)
+
+	fake := newFakeIssuer(t)
+	appConfig, _ := setUpApp(t, fake, `,
+		"rateLimits": {"enabled": false},
+		"notifications": {"expiringDays": 7, "webhooks": [{"url": "`+server.URL+`", "events": ["expiring"]}]}`)
+
+	// certificates of the fake CA expire within a day
+	err := app(appConfig, appOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fake.err = errors.New(`order rejected`)
+	bundleManager := mustGetBundleManager(t, appConfig)
+	_, oldCertificateChain, err := bundleManager.bundleManagers[0].Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_ = renew(appConfig, bundleManager, oldCertificateChain, appOptions{})
+	if atomic.LoadInt32(&expiring) != 0 {
+		t.Fatal(`"expiring" should not be sent on the first failure`)
+	}
+
+	_ = renew(appConfig, bundleManager, oldCertificateChain, appOptions{})
+	if atomic.LoadInt32(&expiring) != 1 {
+		t.Fatal(`"expiring" should be sent when renewal keeps failing`)
+	}
+
+	fake.err = nil
+	err = renew(appConfig, bundleManager, oldCertificateChain, appOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fake.err = errors.New(`order rejected`)
+	_ = renew(appConfig, bundleManager, oldCertificateChain, appOptions{})
+	if atomic.LoadInt32(&expiring) != 1 {
+		t.Fatal(`successful renewal should end the streak of failures`)
+	}
+}
+
+func TestAppDryRunPlacesNoOrder(t *testing.T) {
+	fake := newFakeIssuer(t)
+	appConfig, folder := setUpApp(t, fake, ``)
+
+	err := dryRunApp(appConfig, appOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fake.requests) != 0 {
+		t.Fatal(`dry run should not place an order`)
+	}
+
+	_, err = os.Stat(filepath.Join(folder, `chain.pem`))
+	if !os.IsNotExist(err) {
+		t.Fatal(`dry run should not write certificate files`)
+	}
+}
+
+// countLines returns 0 if file does not exist
+func countLines(t *testing.T, filename string) int {
+	data, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return 0
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Count(string(data), "\n")
+}
+
+const lifecycleHooks = `,
+		"hooks": {
+			"preRenew": [{"command": "echo stop >> {folder}/pre.log"}],
+			"postRenew": [{"command": "echo start >> {folder}/post.log"}],
+			"deploy": [{"command": "echo deploy >> {folder}/deploy.log"}]
+		}`
+
+func TestAppPostRenewHooksAfterFailure(t *testing.T) {
+	fake := newFakeIssuer(t)
+	fake.err = errors.New(`order rejected`)
+	appConfig, folder := setUpApp(t, fake, lifecycleHooks)
+
+	err := app(appConfig, appOptions{})
+	if err == nil {
+		t.Fatal(`issuer error expected`)
+	}
+
+	if countLines(t, filepath.Join(folder, `pre.log`)) != 1 || countLines(t, filepath.Join(folder, `post.log`)) != 1 {
+		t.Fatal(`postRenew hooks should run after failed issuance which passed preRenew`)
+	}
+	if countLines(t, filepath.Join(folder, `deploy.log`)) != 0 {
+		t.Fatal(`deploy hooks should run only for new certificate`)
+	}
+
+	fake.err = nil
+	err = app(appConfig, appOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if countLines(t, filepath.Join(folder, `post.log`)) != 2 || countLines(t, filepath.Join(folder, `deploy.log`)) != 1 {
+		t.Fatal(`deploy and postRenew hooks should run after successful renewal`)
+	}
+}
+
+func TestAppVerifyRetryRunsOnlyPostRenewHooks(t *testing.T) {
+	// test server serves its own certificate, never the issued one
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	t.Cleanup(server.Close)
+
+	fake := newFakeIssuer(t)
+	appConfig, folder := setUpApp(t, fake, lifecycleHooks+`,
+		"verify": {"endpoints": [{"address": "`+server.Listener.Addr().String()+`"}], "reloadRetries": 2, "retryDelay": "0s"}`)
+
+	err := app(appConfig, appOptions{})
+	if err == nil {
+		t.Fatal(`endpoint mismatch expected`)
+	}
+
+	if countLines(t, filepath.Join(folder, `post.log`)) != 3 {
+		t.Fatal(`postRenew hooks should be run again on every reload retry`)
+	}
+	if countLines(t, filepath.Join(folder, `deploy.log`)) != 1 {
+		t.Fatal(`deploy hooks should not be run again on reload retry`)
+	}
+}
+
+func TestAppDryRunReportsRateLimit(t *testing.T) {
+	fake := newFakeIssuer(t)
+	// certificates of the fake CA are always due for renewal
+	appConfig, _ := setUpApp(t, fake, `,
+		"renewBefore": "24h"`)
+
+	err := app(appConfig, appOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = dryRunApp(appConfig, appOptions{})
+	var limitErr *ratelimit.Error
+	if !errors.As(err, &limitErr) {
+		t.Fatal(`rate limit error expected, got: `, err)
+	}
+
+	output := &bytes.Buffer{}
+	printPlannedOrder(output, appConfig, err)
+	if !strings.HasPrefix(output.String(), `acme order: would be refused, duplicate`) {
+		t.Fatal(`refusal should be reported as the planned outcome, got: ` + output.String())
+	}
+}
+
+func mustGetBundleManager(t *testing.T, appConfig config.ConfigInterface) *MultiBundleManager[*rsa.PrivateKey] {
+	bundleManager, err := GenerateMultiBundleManagerFromFormatsSlice[*rsa.PrivateKey](appConfig.GetSaveFormats())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bundleManager
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"ssl/config"
+	"ssl/endpoint"
+	"ssl/hooks"
+	loglib "ssl/logger"
+	"ssl/notify"
+	"time"
+)
+
+// verifyEndpoints checks that configured endpoints serve the deployed certificate.
+// postRenew hooks, which reload servers, are run again on mismatch as many times as reload retries allow.
+// Failure notification is sent if endpoints still serve something else.
+func verifyEndpoints(appConfig config.ConfigInterface, bundleManager *MultiBundleManager[*rsa.PrivateKey], variables map[string]string, dryRun bool) (err error) {
+	endpoints := appConfig.GetVerifyEndpoints()
+	if len(endpoints) < 1 {
+		return nil
+	}
+
+	if dryRun {
+		for _, item := range endpoints {
+			logger.Infof(`dry run: endpoint %s would be verified`, item)
+		}
+		return nil
+	}
+
+	expected, err := getExpectedServedChain(bundleManager)
+	if err != nil {
+		return
+	}
+
+	for attempt := 0; ; attempt++ {
+		err = getEndpointsError(appConfig, endpoints, expected)
+		if err == nil || attempt >= appConfig.GetVerifyReloadRetries() {
+			break
+		}
+
+		delay := appConfig.GetVerifyRetryDelay()
+		logger.Warnf(`endpoints do not serve the new certificate, running reload hooks again in %s`, delay)
+		time.Sleep(delay)
+
+		hookErr := runHooks(hooks.EventPostRenew, appConfig.GetPostRenewHooks(), variables, false)
+		if hookErr != nil {
+			logger.Error(hookErr)
+		}
+	}
+
+	if err != nil {
+		sendNotifications(appConfig, []*notify.Message{newRenewalMessage(appConfig, notify.EventFailure, expected, err)}, false)
+		return
+	}
+
+	logger.Infof(`served certificate verified on %d endpoint(s)`, len(endpoints))
+
+	return
+}
+
+func getExpectedServedChain(bundleManager *MultiBundleManager[*rsa.PrivateKey]) (expected []*x509.Certificate, err error) {
+	certificate, err := bundleManager.GetCertificate()
+	if err != nil {
+		return
+	}
+
+	intermediates, err := bundleManager.GetIntermediates()
+	if err != nil {
+		return
+	}
+
+	return append([]*x509.Certificate{certificate}, intermediates...), nil
+}
+
+// getEndpointsError verifies all endpoints, every mismatch is logged and the first one is returned
+func getEndpointsError(appConfig config.ConfigInterface, endpoints []endpoint.Endpoint, expected []*x509.Certificate) (err error) {
+	for _, item := range endpoints {
+		fields := loglib.Fields{`endpoint`: item.Address, `server_name`: item.ServerName}
+
+		verifyErr := endpoint.Verify(item, expected, appConfig.GetVerifyTimeout())
+		if verifyErr != nil {
+			logger.With(fields).Warnf(`%s`, verifyErr)
+			if err == nil {
+				err = verifyErr
+			}
+			continue
+		}
+
+		logger.With(fields).Debugf(`endpoint serves expected certificate`)
+	}
+
+	return
+}
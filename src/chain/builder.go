@@ -0,0 +1,154 @@
+package chain
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/x509"
+	"errors"
+)
+
+// Build orders certificates so that the leaf goes first followed by its issuers in child-to-parent order.
+// Duplicates and certificates that are not part of the leaf's chain are dropped.
+func Build(certificates []*x509.Certificate) ([]*x509.Certificate, error) {
+	return BuildForPublicKey(nil, certificates)
+}
+
+// BuildForPublicKey works like Build, but picks the leaf whose public key matches the passed one.
+func BuildForPublicKey(publicKey crypto.PublicKey, certificates []*x509.Certificate) (certificateChain []*x509.Certificate, err error) {
+	pool := unique(certificates)
+	if len(pool) < 1 {
+		err = errors.New(`empty certs slice passed`)
+		return
+	}
+
+	leaf, err := findLeaf(publicKey, pool)
+	if err != nil {
+		return
+	}
+
+	used := make(map[*x509.Certificate]bool)
+	certificateChain = append(certificateChain, leaf)
+	used[leaf] = true
+
+	for current := leaf; !isIssuedBy(current, current); {
+		parent := findIssuer(current, pool, used)
+		if parent == nil {
+			break
+		}
+		certificateChain = append(certificateChain, parent)
+		used[parent] = true
+		current = parent
+	}
+
+	return
+}
+
+func unique(certificates []*x509.Certificate) (pool []*x509.Certificate) {
+	pool = make([]*x509.Certificate, 0)
+	for _, certificate := range certificates {
+		if certificate == nil {
+			continue
+		}
+		duplicate := false
+		for _, added := range pool {
+			if added.Equal(certificate) {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			pool = append(pool, certificate)
+		}
+	}
+
+	return
+}
+
+func findLeaf(publicKey crypto.PublicKey, pool []*x509.Certificate) (leaf *x509.Certificate, err error) {
+	candidates := make([]*x509.Certificate, 0)
+	if publicKey != nil {
+		comparableKey, ok := publicKey.(interface{ Equal(crypto.PublicKey) bool })
+		if !ok {
+			err = errors.New(`incomparable public key`)
+			return
+		}
+		for _, certificate := range pool {
+			if comparableKey.Equal(certificate.PublicKey) {
+				candidates = append(candidates, certificate)
+			}
+		}
+		if len(candidates) < 1 {
+			err = errors.New(`no certificate matches private key`)
+			return
+		}
+	} else {
+		for _, certificate := range pool {
+			if !issuesAnyOf(certificate, pool) {
+				candidates = append(candidates, certificate)
+			}
+		}
+		if len(candidates) < 1 {
+			err = errors.New(`leaf certificate not found`)
+			return
+		}
+	}
+
+	nonCA := make([]*x509.Certificate, 0)
+	for _, candidate := range candidates {
+		if !candidate.IsCA {
+			nonCA = append(nonCA, candidate)
+		}
+	}
+
+	if len(nonCA) > 1 || (len(nonCA) < 1 && len(candidates) > 1) {
+		err = errors.New(`ambiguous leaf certificate`)
+		return
+	}
+
+	if len(nonCA) == 1 {
+		leaf = nonCA[0]
+		return
+	}
+
+	leaf = candidates[0]
+
+	return
+}
+
+func findIssuer(child *x509.Certificate, pool []*x509.Certificate, used map[*x509.Certificate]bool) *x509.Certificate {
+	for _, candidate := range pool {
+		if used[candidate] {
+			continue
+		}
+		if isIssuedBy(child, candidate) {
+			return candidate
+		}
+	}
+
+	return nil
+}
+
+func issuesAnyOf(parent *x509.Certificate, pool []*x509.Certificate) bool {
+	for _, child := range pool {
+		if child == parent {
+			continue
+		}
+		if isIssuedBy(child, parent) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isIssuedBy(child, parent *x509.Certificate) bool {
+	if !bytes.Equal(child.RawIssuer, parent.RawSubject) {
+		return false
+	}
+
+	if len(child.AuthorityKeyId) > 0 && len(parent.SubjectKeyId) > 0 && !bytes.Equal(child.AuthorityKeyId, parent.SubjectKeyId) {
+		return false
+	}
+
+	return child.CheckSignatureFrom(parent) == nil
+}
@@ -0,0 +1,118 @@
+package chain
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+)
+
+var (
+	rootKey, intermediateKey, leafKey, otherKey *ecdsa.PrivateKey
+
+	rootCertificate, intermediateCertificate, leafCertificate, otherCertificate *x509.Certificate
+)
+
+func init() {
+	rootKey = generateKey()
+	intermediateKey = generateKey()
+	leafKey = generateKey()
+	otherKey = generateKey()
+
+	rootCertificate = generateCertificate(1, `root`, true, &rootKey.PublicKey, nil, rootKey)
+	intermediateCertificate = generateCertificate(2, `intermediate`, true, &intermediateKey.PublicKey, rootCertificate, rootKey)
+	leafCertificate = generateCertificate(3, `leaf`, false, &leafKey.PublicKey, intermediateCertificate, intermediateKey)
+	otherCertificate = generateCertificate(4, `other`, false, &otherKey.PublicKey, rootCertificate, rootKey)
+}
+
+func TestBuild_Reorder(t *testing.T) {
+	certificateChain, err := Build([]*x509.Certificate{rootCertificate, leafCertificate, intermediateCertificate})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertChain(t, certificateChain, leafCertificate, intermediateCertificate, rootCertificate)
+}
+
+func TestBuild_DropDuplicates(t *testing.T) {
+	certificateChain, err := Build([]*x509.Certificate{intermediateCertificate, leafCertificate, intermediateCertificate, leafCertificate})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertChain(t, certificateChain, leafCertificate, intermediateCertificate)
+}
+
+func TestBuild_Ambiguous(t *testing.T) {
+	_, err := Build([]*x509.Certificate{intermediateCertificate, leafCertificate, otherCertificate})
+	if err == nil {
+		t.Fatal(`ambiguous leaf was not detected`)
+	}
+}
+
+func TestBuildForPublicKey_DropUnrelated(t *testing.T) {
+	certificateChain, err := BuildForPublicKey(&leafKey.PublicKey, []*x509.Certificate{otherCertificate, intermediateCertificate, leafCertificate})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertChain(t, certificateChain, leafCertificate, intermediateCertificate)
+}
+
+func TestBuildForPublicKey_NoMatch(t *testing.T) {
+	_, err := BuildForPublicKey(&otherKey.PublicKey, []*x509.Certificate{intermediateCertificate, leafCertificate})
+	if err == nil {
+		t.Fatal(`missing leaf was not detected`)
+	}
+}
+
+func assertChain(t *testing.T, certificateChain []*x509.Certificate, reference ...*x509.Certificate) {
+	t.Helper()
+	if len(certificateChain) != len(reference) {
+		t.Fatalf(`chain length is %d, but %d expected`, len(certificateChain), len(reference))
+	}
+	for i := range reference {
+		if !certificateChain[i].Equal(reference[i]) {
+			t.Fatalf(`certificate %d is "%s", but "%s" expected`, i, certificateChain[i].Subject.CommonName, reference[i].Subject.CommonName)
+		}
+	}
+}
+
+func generateKey() *ecdsa.PrivateKey {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	return key
+}
+
+func generateCertificate(serial int64, commonName string, isCA bool, publicKey *ecdsa.PublicKey, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) *x509.Certificate {
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(serial),
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		BasicConstraintsValid: true,
+		IsCA:                  isCA,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+	}
+	if parent == nil {
+		parent = template
+	}
+
+	raw, err := x509.CreateCertificate(rand.Reader, template, parent, publicKey, parentKey)
+	if err != nil {
+		panic(err)
+	}
+
+	certificate, err := x509.ParseCertificate(raw)
+	if err != nil {
+		panic(err)
+	}
+
+	return certificate
+}
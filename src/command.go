@@ -0,0 +1,223 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"ssl/config"
+	"strings"
+)
+
+const defaultCommandName = `renew`
+
+type command struct {
+	description string
+	// setFlags registers command specific flags, values are read by run
+	setFlags func(flags *flag.FlagSet)
+	run      func(appConfig config.ConfigInterface, args []string) error
+	// printsResult marks commands writing their result to stdout, so logs have to go elsewhere
+	printsResult bool
+}
+
+var commands map[string]*command
+
+func init() {
+	commands = map[string]*command{
+		`renew`:           newRenewCommand(),
+		`sync`:            newSyncCommand(),
+		`status`:          newStatusCommand(),
+		`check`:           newCheckCommand(),
+		`verify`:          newVerifyCommand(),
+		`revoke`:          newRevokeCommand(),
+		`import`:          newImportCommand(),
+		`export`:          newExportCommand(),
+		`config validate`: newConfigValidateCommand(),
+		`config explain`:  newConfigExplainCommand(),
+		`ca export`:       newCAExportCommand(),
+		`daemon`:          newDaemonCommand(),
+	}
+}
+
+type invocation struct {
+	command   *command
+	args      []string
+	overrides config.Overrides
+}
+
+// configFlags map command line flags to config fields
+var configFlags = []struct {
+	name   string
+	path   string
+	usage  string
+	isBool bool
+}{
+	{name: `email`, path: `email`, usage: `account email`},
+	{name: `domains`, path: `domains`, usage: `comma separated list of domains`},
+	{name: `port`, path: `port`, usage: `port for http-01 challenge server`},
+	{name: `key-length`, path: `keyLength`, usage: `rsa key length`},
+	{name: `cert-days-left-min`, path: `certDaysLeftMin`, usage: `minimal days left before renewal (deprecated, use renew-before)`},
+	{name: `renew-before`, path: `renewBefore`, usage: `renew when less than this duration is left, e.g. "720h"`},
+	{name: `renew-at`, path: `renewAt`, usage: `renew after this fraction of validity period has passed, e.g. 0.66`},
+	{name: `staging`, path: `useStaging`, usage: `use staging CA`, isBool: true},
+	{name: `account-key`, path: `accountKeyFilename`, usage: `account key filename`},
+}
+
+func parseCommandLine(args []string) (inv *invocation, err error) {
+	inv = &invocation{
+		overrides: config.Overrides{Values: make(map[string]string)},
+	}
+
+	globalFlags := flag.NewFlagSet(getAppName(), flag.ContinueOnError)
+	registerGlobalFlags(globalFlags, &inv.overrides)
+	globalFlags.Usage = func() {
+		printUsage(globalFlags)
+	}
+	err = globalFlags.Parse(args)
+	if err != nil {
+		return
+	}
+
+	commandName, commandArgs := splitCommand(globalFlags.Args())
+	inv.command = commands[commandName]
+	if inv.command == nil {
+		err = errors.New(`unknown command "` + commandName + `"`)
+		return
+	}
+
+	commandFlags := flag.NewFlagSet(getAppName()+` `+commandName, flag.ContinueOnError)
+	registerGlobalFlags(commandFlags, &inv.overrides)
+	if inv.command.setFlags != nil {
+		inv.command.setFlags(commandFlags)
+	}
+	commandFlags.Usage = func() {
+		_, _ = fmt.Fprintf(commandFlags.Output(), "Usage: %s [flags]\n\n%s\n\nFlags:\n", commandFlags.Name(), inv.command.description)
+		commandFlags.PrintDefaults()
+	}
+	err = commandFlags.Parse(commandArgs)
+	if err != nil {
+		return
+	}
+
+	inv.args = commandFlags.Args()
+
+	return
+}
+
+func splitCommand(args []string) (commandName string, commandArgs []string) {
+	if len(args) < 1 {
+		return defaultCommandName, args
+	}
+
+	if len(args) > 1 {
+		if _, exists := commands[args[0]+` `+args[1]]; exists {
+			return args[0] + ` ` + args[1], args[2:]
+		}
+	}
+
+	return args[0], args[1:]
+}
+
+// registerGlobalFlags uses flag.Var only, as it does not reset values already parsed by another flag set
+func registerGlobalFlags(flags *flag.FlagSet, overrides *config.Overrides) {
+	flags.Var(&pathFlag{value: &overrides.ConfigFolder}, `config-dir`, `config files folder`)
+	flags.Var(&stringFlag{value: &overrides.Env}, `env`, `environment: dev or prod`)
+	flags.Var(&setFlag{values: overrides.Values}, `set`, `override any config value, e.g. "saveFormats.0.folder=/etc/ssl" (repeatable)`)
+	for _, configFlag := range configFlags {
+		flags.Var(&overrideFlag{path: configFlag.path, values: overrides.Values, isBool: configFlag.isBool}, configFlag.name, configFlag.usage)
+	}
+}
+
+func printUsage(globalFlags *flag.FlagSet) {
+	output := globalFlags.Output()
+	_, _ = fmt.Fprintf(output, "Usage: %s [flags] [command] [command flags]\n\nCommands:\n", getAppName())
+
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		description := commands[name].description
+		if name == defaultCommandName {
+			description += ` (default)`
+		}
+		_, _ = fmt.Fprintf(output, "  %-16s %s\n", name, description)
+	}
+
+	_, _ = fmt.Fprintln(output, "\nFlags:")
+	globalFlags.PrintDefaults()
+}
+
+func getAppName() string {
+	return filepath.Base(os.Args[0])
+}
+
+type stringFlag struct {
+	value *string
+}
+
+func (f *stringFlag) String() string {
+	if f.value == nil {
+		return ``
+	}
+	return *f.value
+}
+
+func (f *stringFlag) Set(value string) error {
+	*f.value = value
+	return nil
+}
+
+// pathFlag resolves relative paths from working directory
+type pathFlag stringFlag
+
+func (f *pathFlag) String() string {
+	return (*stringFlag)(f).String()
+}
+
+func (f *pathFlag) Set(value string) (err error) {
+	*f.value, err = filepath.Abs(value)
+	return
+}
+
+type overrideFlag struct {
+	path   string
+	values map[string]string
+	isBool bool
+}
+
+func (f *overrideFlag) String() string {
+	if f.values == nil {
+		return ``
+	}
+	return f.values[f.path]
+}
+
+func (f *overrideFlag) Set(value string) error {
+	f.values[f.path] = value
+	return nil
+}
+
+func (f *overrideFlag) IsBoolFlag() bool {
+	return f.isBool
+}
+
+type setFlag struct {
+	values map[string]string
+}
+
+func (f *setFlag) String() string {
+	return ``
+}
+
+func (f *setFlag) Set(value string) error {
+	path, pathValue, found := strings.Cut(value, `=`)
+	if !found || path == `` {
+		return errors.New(`value should look like "path=value"`)
+	}
+	f.values[path] = pathValue
+	return nil
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"encoding/pem"
+	"errors"
+	"flag"
+	"os"
+	"ssl/config"
+	"ssl/converters"
+	"ssl/storage/file"
+)
+
+func newCAExportCommand() *command {
+	format := exportFormatPEM
+	output := ``
+
+	return &command{
+		description: `write local CA certificate for installing into development trust stores, CA is generated if missing`,
+		setFlags: func(flags *flag.FlagSet) {
+			flags.StringVar(&format, `format`, exportFormatPEM, `output encoding: pem or der`)
+			flags.StringVar(&output, `out`, ``, `output filename, stdout if empty`)
+		},
+		printsResult: true,
+		run: func(appConfig config.ConfigInterface, args []string) (err error) {
+			if len(args) > 0 {
+				return errors.New(`unexpected arguments passed`)
+			}
+			if !appConfig.GetLocalCAEnabled() {
+				return errors.New(`local CA is not enabled`)
+			}
+
+			ca, err := getOrCreateLocalCA(appConfig)
+			if err != nil {
+				return
+			}
+
+			var data []byte
+			switch format {
+			case exportFormatPEM:
+				var pemBlock *pem.Block
+				pemBlock, err = converters.CertificateToPEMBlock(ca.Certificate)
+				if err != nil {
+					return
+				}
+				data = pem.EncodeToMemory(pemBlock)
+			case exportFormatDER:
+				data = ca.Certificate.Raw
+			default:
+				return errors.New(`unsupported format "` + format + `", use pem or der`)
+			}
+
+			if output == `` {
+				_, err = os.Stdout.Write(data)
+				return
+			}
+
+			store, err := file.NewByteFile(output, exportCertificatePermissions)
+			if err != nil {
+				return
+			}
+
+			return store.Save(data)
+		},
+	}
+}
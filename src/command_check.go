@@ -0,0 +1,43 @@
+package main
+
+import (
+	"crypto/rsa"
+	"errors"
+	"ssl/config"
+	"ssl/validations"
+)
+
+func newCheckCommand() *command {
+	return &command{
+		description: `validate current certificate bundle without changing anything`,
+		run: func(appConfig config.ConfigInterface, args []string) (err error) {
+			if len(args) > 0 {
+				return errors.New(`unexpected arguments passed`)
+			}
+
+			bundleManager, err := GenerateMultiBundleManagerFromFormatsSlice[*rsa.PrivateKey](appConfig.GetSaveFormats())
+			if err != nil {
+				return
+			}
+
+			certKey, certificateChain, err := bundleManager.Get()
+			if err != nil {
+				return
+			}
+
+			err = validations.GetCertificateBundleValidationError(certKey, certificateChain, appConfig.GetDomains(), getRenewalThreshold(appConfig))
+			if err != nil {
+				return
+			}
+
+			err = getRevocationError(appConfig, certificateChain)
+			if err != nil {
+				return
+			}
+
+			logger.Infof(`certificate bundle is ok`)
+
+			return
+		},
+	}
+}
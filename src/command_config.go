@@ -0,0 +1,63 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"ssl/config"
+	"strconv"
+	"text/tabwriter"
+)
+
+func newConfigValidateCommand() *command {
+	return &command{
+		description:  `validate configuration and exit`,
+		printsResult: true,
+		run: func(appConfig config.ConfigInterface, args []string) (err error) {
+			if len(args) > 0 {
+				return errors.New(`unexpected arguments passed`)
+			}
+
+			_, err = fmt.Fprintln(os.Stdout, `config is valid`)
+
+			return
+		},
+	}
+}
+
+func newConfigExplainCommand() *command {
+	asJSON := false
+
+	return &command{
+		description: `print final value and source (default, file, env or flag) of every config field`,
+		setFlags: func(flags *flag.FlagSet) {
+			flags.BoolVar(&asJSON, `json`, false, `print values as json`)
+		},
+		printsResult: true,
+		run: func(appConfig config.ConfigInterface, args []string) error {
+			if len(args) > 0 {
+				return errors.New(`unexpected arguments passed`)
+			}
+			return printConfigExplanation(os.Stdout, appConfig.Explain(), asJSON)
+		},
+	}
+}
+
+func printConfigExplanation(w io.Writer, values []*config.ExplainedValue, asJSON bool) error {
+	if asJSON {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent(``, `  `)
+		return encoder.Encode(values)
+	}
+
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(table, "PATH\tVALUE\tSOURCE")
+	for _, value := range values {
+		_, _ = fmt.Fprintf(table, "%s\t%s\t%s\n", value.Path, strconv.Quote(value.Value), value.Source)
+	}
+
+	return table.Flush()
+}
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"flag"
+	"os"
+	"ssl/config"
+	"ssl/converters"
+	"ssl/storage/file"
+	"ssl/validations"
+	"strings"
+)
+
+const (
+	exportFormatPEM    = `pem`
+	exportFormatDER    = `der`
+	exportFormatPKCS12 = `pkcs12`
+	exportFormatJKS    = `jks`
+	exportFormatPKCS7  = `pkcs7`
+)
+
+const (
+	exportPartKey           = `key`
+	exportPartCertificate   = `certificate`
+	exportPartIntermediates = `intermediates`
+)
+
+const (
+	exportKeyPermissions         os.FileMode = 0600
+	exportCertificatePermissions os.FileMode = 0644
+)
+
+type exportOptions struct {
+	format   string
+	output   string
+	include  string
+	password string
+	alias    string
+}
+
+func newExportCommand() *command {
+	options := &exportOptions{}
+
+	return &command{
+		description: `write current certificate bundle to a file in any supported encoding`,
+		setFlags: func(flags *flag.FlagSet) {
+			flags.StringVar(&options.format, `format`, exportFormatPEM, `output encoding: pem, der, pkcs12, jks or pkcs7`)
+			flags.StringVar(&options.output, `out`, ``, `output filename`)
+			flags.StringVar(&options.include, `include`, ``, `comma separated parts to export for pem and der: key, certificate, intermediates`)
+			flags.StringVar(&options.password, `password`, ``, `password for pkcs12 and jks`)
+			flags.StringVar(&options.alias, `alias`, `certificate`, `private key entry alias for jks`)
+		},
+		run: func(appConfig config.ConfigInterface, args []string) error {
+			if len(args) > 0 {
+				return errors.New(`unexpected arguments passed`)
+			}
+			return exportBundle(appConfig, options)
+		},
+	}
+}
+
+func exportBundle(appConfig config.ConfigInterface, options *exportOptions) (err error) {
+	if options.output == `` {
+		return errors.New(`output filename is not passed`)
+	}
+
+	parts, err := getExportParts(options.format, options.include)
+	if err != nil {
+		return
+	}
+
+	bundleManager, err := GenerateMultiBundleManagerFromFormatsSlice[*rsa.PrivateKey](appConfig.GetSaveFormats())
+	if err != nil {
+		return
+	}
+
+	key, certificateChain, err := bundleManager.Get()
+	if err != nil {
+		return
+	}
+
+	err = validations.GetBasicRSAPrivateKeyError(key)
+	if err != nil {
+		return
+	}
+
+	err = validations.GetBasicCertificateChainError(certificateChain)
+	if err != nil {
+		return
+	}
+
+	data, err := encodeExport(options.format, parts, key, certificateChain, options.password, options.alias)
+	if err != nil {
+		return
+	}
+
+	permissions := exportCertificatePermissions
+	if parts[exportPartKey] {
+		permissions = exportKeyPermissions
+	}
+
+	store, err := file.NewByteFile(options.output, permissions)
+	if err != nil {
+		return
+	}
+
+	err = store.Save(data)
+	if err != nil {
+		return
+	}
+
+	logger.Infof(`certificate "%s" exported to "%s" as %s`, certificateChain[0].Subject.CommonName, options.output, options.format)
+
+	return
+}
+
+func getExportParts(format string, include string) (parts map[string]bool, err error) {
+	parts = make(map[string]bool)
+
+	switch format {
+	case exportFormatPKCS12, exportFormatJKS:
+		parts[exportPartKey] = true
+		parts[exportPartCertificate] = true
+		parts[exportPartIntermediates] = true
+	case exportFormatPKCS7:
+		parts[exportPartCertificate] = true
+		parts[exportPartIntermediates] = true
+	case exportFormatPEM:
+		if include == `` {
+			include = strings.Join([]string{exportPartKey, exportPartCertificate, exportPartIntermediates}, `,`)
+		}
+	case exportFormatDER:
+		if include == `` {
+			include = exportPartCertificate
+		}
+	default:
+		err = errors.New(`unknown export format "` + format + `"`)
+		return
+	}
+
+	if format != exportFormatPEM && format != exportFormatDER {
+		if include != `` {
+			err = errors.New(`parts selection is supported for pem and der only`)
+		}
+		return
+	}
+
+	for _, part := range strings.Split(include, `,`) {
+		part = strings.TrimSpace(part)
+		if part != exportPartKey && part != exportPartCertificate && part != exportPartIntermediates {
+			err = errors.New(`unknown export part "` + part + `"`)
+			return
+		}
+		parts[part] = true
+	}
+
+	if format == exportFormatDER && parts[exportPartKey] && len(parts) > 1 {
+		err = errors.New(`der can not hold private key together with certificates`)
+	}
+
+	return
+}
+
+func encodeExport(format string, parts map[string]bool, key *rsa.PrivateKey, certificateChain []*x509.Certificate, password string, alias string) (data []byte, err error) {
+	certificates := make([]*x509.Certificate, 0)
+	if parts[exportPartCertificate] {
+		certificates = append(certificates, certificateChain[0])
+	}
+	if parts[exportPartIntermediates] {
+		certificates = append(certificates, certificateChain[1:]...)
+	}
+
+	switch format {
+	case exportFormatPKCS12:
+		return converters.KeyAndCertificatesToPKCS12(key, certificateChain, password)
+	case exportFormatJKS:
+		return converters.KeyAndCertificatesToJKS(key, certificateChain, alias, password)
+	case exportFormatPKCS7:
+		return converters.CertificatesToPKCS7(certificates)
+	case exportFormatDER:
+		if parts[exportPartKey] {
+			return converters.PrivateKeyToDER(key)
+		}
+		return converters.CertificatesToDER(certificates)
+	}
+
+	pemBlocks := make([]*pem.Block, 0)
+	if parts[exportPartKey] {
+		var keyBlock *pem.Block
+		keyBlock, err = converters.PrivateKeyToPEMBlock(key)
+		if err != nil {
+			return
+		}
+		pemBlocks = append(pemBlocks, keyBlock)
+	}
+
+	certificateBlocks, errs := converters.CertificatesToPEMBlocks(certificates)
+	if len(errs) > 0 {
+		err = errs[0]
+		return
+	}
+	pemBlocks = append(pemBlocks, certificateBlocks...)
+
+	if len(pemBlocks) < 1 {
+		err = errors.New(`nothing to export`)
+		return
+	}
+
+	for _, pemBlock := range pemBlocks {
+		data = append(data, pem.EncodeToMemory(pemBlock)...)
+	}
+
+	return
+}
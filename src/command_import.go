@@ -0,0 +1,160 @@
+package main
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/x509"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"ssl/chain"
+	"ssl/common"
+	"ssl/config"
+	"ssl/converters"
+	loglib "ssl/logger"
+	"ssl/validations"
+)
+
+var certbotLiveFilenames = []string{`privkey.pem`, `cert.pem`, `chain.pem`, `fullchain.pem`}
+
+func newImportCommand() *command {
+	password := ``
+
+	return &command{
+		description: `import key and certificates from PEM, DER, PKCS#12 files or certbot "live/<name>" folders`,
+		setFlags: func(flags *flag.FlagSet) {
+			flags.StringVar(&password, `password`, ``, `password for PKCS#12 inputs`)
+		},
+		run: func(appConfig config.ConfigInterface, args []string) error {
+			return importBundle(appConfig, args, password)
+		},
+	}
+}
+
+func importBundle(appConfig config.ConfigInterface, paths []string, password string) (err error) {
+	if len(paths) < 1 {
+		return errors.New(`no files to import passed`)
+	}
+
+	keys, certificates, err := readImportInputs(paths, password)
+	if err != nil {
+		return
+	}
+
+	key, certificateChain, err := selectImportedBundle(keys, certificates)
+	if err != nil {
+		return
+	}
+
+	err = validations.GetCertificateBundleValidationError(key, certificateChain, appConfig.GetDomains(), getRenewalThreshold(appConfig))
+	if err != nil {
+		return errors.New(`imported bundle is invalid: ` + err.Error())
+	}
+
+	bundleManager, err := GenerateMultiBundleManagerFromFormatsSlice[*rsa.PrivateKey](appConfig.GetSaveFormats())
+	if err != nil {
+		return
+	}
+
+	err = bundleManager.Set(key, certificateChain)
+	if err != nil {
+		return
+	}
+
+	logger.With(loglib.Fields{`serial`: getCertificateChainSerial(certificateChain)}).Infof(`certificate "%s" with %d intermediate(s) imported`, certificateChain[0].Subject.CommonName, len(certificateChain)-1)
+
+	return
+}
+
+func readImportInputs(paths []string, password string) (keys []crypto.PrivateKey, certificates []*x509.Certificate, err error) {
+	filenames, err := expandImportPaths(paths)
+	if err != nil {
+		return
+	}
+
+	var data []byte
+	var fileKeys []crypto.PrivateKey
+	var fileCertificates []*x509.Certificate
+	for _, filename := range filenames {
+		data, err = os.ReadFile(filename)
+		if err != nil {
+			return
+		}
+
+		fileKeys, fileCertificates, err = converters.DecodeKeysAndCertificates(data, password)
+		if err != nil {
+			err = errors.New(`file "` + filename + `": ` + err.Error())
+			return
+		}
+
+		logger.With(loglib.Fields{`file`: filename}).Debugf(`file "%s" contains %d key(s) and %d certificate(s)`, filename, len(fileKeys), len(fileCertificates))
+
+		keys = append(keys, fileKeys...)
+		certificates = append(certificates, fileCertificates...)
+	}
+
+	return
+}
+
+// expandImportPaths replaces directories (like certbot "live/<name>") with the certificate files inside them
+func expandImportPaths(paths []string) (filenames []string, err error) {
+	var isDir, exists bool
+	for _, path := range paths {
+		isDir, err = common.DirectoryExists(path)
+		if err != nil {
+			return
+		}
+
+		if !isDir {
+			filenames = append(filenames, path)
+			continue
+		}
+
+		found := false
+		for _, certbotFilename := range certbotLiveFilenames {
+			filename := filepath.Join(path, certbotFilename)
+			exists, err = common.FileExists(filename)
+			if err != nil {
+				return
+			}
+			if exists {
+				filenames = append(filenames, filename)
+				found = true
+			}
+		}
+
+		if !found {
+			err = errors.New(`folder "` + path + `" does not contain certificate files`)
+			return
+		}
+	}
+
+	return
+}
+
+func selectImportedBundle(keys []crypto.PrivateKey, certificates []*x509.Certificate) (key *rsa.PrivateKey, certificateChain []*x509.Certificate, err error) {
+	rsaKeyFound := false
+	for _, anyKey := range keys {
+		rsaKey, ok := anyKey.(*rsa.PrivateKey)
+		if !ok {
+			continue
+		}
+		rsaKeyFound = true
+
+		certificateChain, err = chain.BuildForPublicKey(rsaKey.Public(), certificates)
+		if err == nil {
+			key = rsaKey
+			return
+		}
+	}
+
+	if !rsaKeyFound {
+		err = errors.New(`no rsa private key found in imported files`)
+		return
+	}
+
+	err = errors.New(`no certificate matches imported private keys`)
+
+	return
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"ssl/issuer"
+	"strings"
+	"testing"
+)
+
+// writeCertbotLiveFolder mimics "live/<name>" folder of certbot with a bundle of the fake CA
+func writeCertbotLiveFolder(t *testing.T, fake *fakeIssuer) (folder string, key *rsa.PrivateKey, certificates []*x509.Certificate) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certificates, err = fake.Obtain(issuer.Request{Identifiers: []string{`example.com`, `www.example.com`}, Key: key})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encode := func(blocks ...*pem.Block) []byte {
+		buffer := &bytes.Buffer{}
+		for _, block := range blocks {
+			_ = pem.Encode(buffer, block)
+		}
+		return buffer.Bytes()
+	}
+	leaf := &pem.Block{Type: `CERTIFICATE`, Bytes: certificates[0].Raw}
+	intermediate := &pem.Block{Type: `CERTIFICATE`, Bytes: certificates[1].Raw}
+
+	folder = filepath.Join(t.TempDir(), `live`, `example.com`)
+	err = os.MkdirAll(folder, 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files := map[string][]byte{
+		`privkey.pem`:   encode(&pem.Block{Type: `RSA PRIVATE KEY`, Bytes: x509.MarshalPKCS1PrivateKey(key)}),
+		`cert.pem`:      encode(leaf),
+		`chain.pem`:     encode(intermediate),
+		`fullchain.pem`: encode(leaf, intermediate),
+		`README`:        []byte(`certbot readme`),
+	}
+	for name, data := range files {
+		err = os.WriteFile(filepath.Join(folder, name), data, 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return
+}
+
+func TestExpandImportPaths(t *testing.T) {
+	folder, _, _ := writeCertbotLiveFolder(t, newFakeIssuer(t))
+	single := filepath.Join(folder, `cert.pem`)
+
+	filenames, err := expandImportPaths([]string{folder, single})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{`privkey.pem`, `cert.pem`, `chain.pem`, `fullchain.pem`}
+	if len(filenames) != len(expected)+1 || filenames[len(expected)] != single {
+		t.Fatal(`certbot files and the single file expected, got: ` + strings.Join(filenames, `,`))
+	}
+	for i, name := range expected {
+		if filenames[i] != filepath.Join(folder, name) {
+			t.Fatal(`certbot files expected in order, got: ` + strings.Join(filenames, `,`))
+		}
+	}
+
+	_, err = expandImportPaths([]string{t.TempDir()})
+	if err == nil {
+		t.Fatal(`folder without certificate files was accepted`)
+	}
+}
+
+func TestSelectImportedBundle(t *testing.T) {
+	_, key, certificates := writeCertbotLiveFolder(t, newFakeIssuer(t))
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// issuer certificate first, so the leaf has to be found by the key
+	reordered := []*x509.Certificate{certificates[1], certificates[0]}
+	selectedKey, certificateChain, err := selectImportedBundle([]crypto.PrivateKey{otherKey, key}, reordered)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !selectedKey.Equal(key) || len(certificateChain) < 1 || !bytes.Equal(certificateChain[0].Raw, certificates[0].Raw) {
+		t.Fatal(`key should be matched with its leaf certificate`)
+	}
+
+	_, _, err = selectImportedBundle([]crypto.PrivateKey{otherKey}, certificates)
+	if err == nil || !strings.Contains(err.Error(), `no certificate matches`) {
+		t.Fatal(`mismatched key should be rejected, got: `, err)
+	}
+
+	_, _, err = selectImportedBundle(nil, certificates)
+	if err == nil || !strings.Contains(err.Error(), `no rsa private key`) {
+		t.Fatal(`missing key should be rejected, got: `, err)
+	}
+}
+
+func TestImportBundle_CertbotFolder(t *testing.T) {
+	fake := newFakeIssuer(t)
+	appConfig, folder := setUpApp(t, fake, ``)
+	liveFolder, key, certificates := writeCertbotLiveFolder(t, fake)
+
+	err := importBundle(appConfig, []string{liveFolder}, ``)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	savedKey, certificateChain, err := mustGetBundleManager(t, appConfig).bundleManagers[0].Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !savedKey.Equal(key) || len(certificateChain) < 1 || !bytes.Equal(certificateChain[0].Raw, certificates[0].Raw) {
+		t.Fatal(`imported bundle was not saved`)
+	}
+
+	_, err = os.Stat(filepath.Join(folder, `chain.pem`))
+	if err != nil {
+		t.Fatal(`save format files should be written`)
+	}
+
+	err = importBundle(appConfig, []string{filepath.Join(liveFolder, `cert.pem`)}, ``)
+	if err == nil {
+		t.Fatal(`certificate without key was imported`)
+	}
+
+	// the only order is the one of the fixture
+	if len(fake.requests) != 1 {
+		t.Fatal(`import should not place orders`)
+	}
+}
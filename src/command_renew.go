@@ -0,0 +1,34 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"ssl/config"
+)
+
+func newRenewCommand() *command {
+	options := appOptions{}
+	dryRun := false
+
+	return &command{
+		description: `sync save formats and renew certificate if it is invalid`,
+		setFlags: func(flags *flag.FlagSet) {
+			flags.BoolVar(&options.force, `force`, false, `renew certificate regardless of validation and local rate limits`)
+			flags.BoolVar(&dryRun, `dry-run`, false, `print planned changes instead of applying them, no order is placed at CA`)
+		},
+		run: func(appConfig config.ConfigInterface, args []string) error {
+			if len(args) > 0 {
+				return errors.New(`unexpected arguments passed`)
+			}
+
+			if dryRun {
+				return dryRunApp(appConfig, options)
+			}
+
+			err := app(appConfig, options)
+			writeMetricsTextfile(appConfig)
+
+			return err
+		},
+	}
+}
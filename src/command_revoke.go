@@ -0,0 +1,55 @@
+package main
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"errors"
+	"flag"
+	"ssl/config"
+	"ssl/validations"
+)
+
+func newRevokeCommand() *command {
+	reason := uint(0)
+
+	return &command{
+		description: `revoke current certificate at CA`,
+		setFlags: func(flags *flag.FlagSet) {
+			flags.UintVar(&reason, `reason`, 0, `RFC 5280 revocation reason code`)
+		},
+		run: func(appConfig config.ConfigInterface, args []string) (err error) {
+			if len(args) > 0 {
+				return errors.New(`unexpected arguments passed`)
+			}
+
+			bundleManager, err := GenerateMultiBundleManagerFromFormatsSlice[*rsa.PrivateKey](appConfig.GetSaveFormats())
+			if err != nil {
+				return
+			}
+
+			certificate, err := bundleManager.GetCertificate()
+			if err != nil {
+				return
+			}
+
+			err = validations.GetBasicCertificateChainError([]*x509.Certificate{certificate})
+			if err != nil {
+				return
+			}
+
+			certificateIssuer, err := newIssuer(appConfig)
+			if err != nil {
+				return
+			}
+
+			err = certificateIssuer.Revoke(certificate, int(reason))
+			if err != nil {
+				return
+			}
+
+			logger.Infof(`certificate "%s" with serial "%s" revoked`, certificate.Subject.CommonName, certificate.SerialNumber.Text(16))
+
+			return
+		},
+	}
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"ssl/config"
+	"ssl/managers"
+	"ssl/validations"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+type bundleStatus struct {
+	Format     int       `json:"format"`
+	Subject    string    `json:"subject"`
+	SANs       []string  `json:"sans"`
+	Issuer     string    `json:"issuer"`
+	Serial     string    `json:"serial"`
+	NotBefore  time.Time `json:"notBefore"`
+	NotAfter   time.Time `json:"notAfter"`
+	DaysLeft   int       `json:"daysLeft"`
+	KeyType    string    `json:"keyType"`
+	KeySize    int       `json:"keySize"`
+	KeyMatch   bool      `json:"keyMatch"`
+	ChainValid bool      `json:"chainValid"`
+	ChainError string    `json:"chainError,omitempty"`
+	Revocation string    `json:"revocation,omitempty"`
+	NeedSync   bool      `json:"needSync"`
+	Error      string    `json:"error,omitempty"`
+}
+
+func newStatusCommand() *command {
+	asJSON := false
+
+	return &command{
+		description: `print certificate details of every save format`,
+		setFlags: func(flags *flag.FlagSet) {
+			flags.BoolVar(&asJSON, `json`, false, `print status as json`)
+		},
+		run: func(appConfig config.ConfigInterface, args []string) error {
+			if len(args) > 0 {
+				return errors.New(`unexpected arguments passed`)
+			}
+			return printStatus(appConfig, asJSON)
+		},
+		printsResult: true,
+	}
+}
+
+func printStatus(appConfig config.ConfigInterface, asJSON bool) (err error) {
+	bundleManager, err := GenerateMultiBundleManagerFromFormatsSlice[*rsa.PrivateKey](appConfig.GetSaveFormats())
+	if err != nil {
+		return
+	}
+
+	statuses := make([]*bundleStatus, 0, len(bundleManager.bundleManagers))
+	for num, mgr := range bundleManager.bundleManagers {
+		statuses = append(statuses, getBundleStatus(num, mgr))
+	}
+
+	if appConfig.GetRevocationCheckEnabled() && !appConfig.GetLocalCAEnabled() {
+		setRevocationStatuses(appConfig, bundleManager, statuses)
+	}
+
+	if asJSON {
+		return printStatusesJSON(os.Stdout, statuses)
+	}
+
+	return printStatusesTable(os.Stdout, statuses)
+}
+
+func getBundleStatus(format int, mgr managers.Bundle[*rsa.PrivateKey]) (status *bundleStatus) {
+	status = &bundleStatus{
+		Format:   format,
+		NeedSync: mgr.NeedSync(),
+	}
+
+	key, certificateChain, err := mgr.Get()
+	if err != nil {
+		status.Error = err.Error()
+		return
+	}
+
+	err = validations.GetCertificateChainError(certificateChain)
+	status.ChainValid = err == nil
+	if err != nil {
+		status.ChainError = err.Error()
+	}
+
+	if len(certificateChain) < 1 || certificateChain[0] == nil {
+		status.Error = `certificate not found`
+		return
+	}
+
+	certificate := certificateChain[0]
+	status.Subject = certificate.Subject.String()
+	status.SANs = getCertificateSANs(certificate)
+	status.Issuer = certificate.Issuer.String()
+	status.Serial = certificate.SerialNumber.Text(16)
+	status.NotBefore = certificate.NotBefore
+	status.NotAfter = certificate.NotAfter
+	status.DaysLeft = int(time.Until(certificate.NotAfter).Hours() / 24)
+	status.KeyType, status.KeySize = getPublicKeyTypeAndSize(certificate.PublicKey)
+
+	if key != nil {
+		status.KeyMatch = validations.GetPrivateKeyMatchCertificateError(certificate, key) == nil
+	}
+
+	return
+}
+
+// setRevocationStatuses checks every distinct certificate once, formats usually share the same one
+func setRevocationStatuses(appConfig config.ConfigInterface, bundleManager *MultiBundleManager[*rsa.PrivateKey], statuses []*bundleStatus) {
+	known := make(map[string]string)
+	for num, mgr := range bundleManager.bundleManagers {
+		status := statuses[num]
+		if status.Serial == `` {
+			continue
+		}
+
+		revocationStatus, checked := known[status.Serial]
+		if !checked {
+			_, certificateChain, err := mgr.Get()
+			if err != nil {
+				continue
+			}
+
+			result, err := checkRevocation(appConfig, certificateChain)
+			revocationStatus = string(result.Status)
+			if err != nil {
+				logger.Warnf(`revocation status check failed: %s`, err)
+			}
+			known[status.Serial] = revocationStatus
+		}
+
+		status.Revocation = revocationStatus
+	}
+}
+
+func getCertificateSANs(certificate *x509.Certificate) (sans []string) {
+	sans = make([]string, 0, len(certificate.DNSNames)+len(certificate.IPAddresses))
+	sans = append(sans, certificate.DNSNames...)
+	for _, ip := range certificate.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+
+	return
+}
+
+func getPublicKeyTypeAndSize(publicKey any) (keyType string, keySize int) {
+	switch typedKey := publicKey.(type) {
+	case *rsa.PublicKey:
+		return `RSA`, typedKey.N.BitLen()
+	case *ecdsa.PublicKey:
+		return `ECDSA`, typedKey.Curve.Params().BitSize
+	case ed25519.PublicKey:
+		return `Ed25519`, 256
+	default:
+		return `unknown`, 0
+	}
+}
+
+func printStatusesJSON(w io.Writer, statuses []*bundleStatus) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent(``, `  `)
+	return encoder.Encode(statuses)
+}
+
+func printStatusesTable(w io.Writer, statuses []*bundleStatus) error {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(table, "FORMAT\tSUBJECT\tSANS\tISSUER\tSERIAL\tNOT BEFORE\tNOT AFTER\tDAYS LEFT\tKEY\tKEY MATCH\tCHAIN\tREVOCATION\tNEED SYNC\tERROR")
+	for _, status := range statuses {
+		chainStatus := `valid`
+		if !status.ChainValid {
+			chainStatus = `invalid`
+		}
+		key := ``
+		if status.KeyType != `` {
+			key = status.KeyType + ` ` + strconv.Itoa(status.KeySize)
+		}
+		_, _ = fmt.Fprintf(
+			table,
+			"%d\t%s\t%s\t%s\t%s\t%s\t%s\t%d\t%s\t%t\t%s\t%s\t%t\t%s\n",
+			status.Format,
+			status.Subject,
+			strings.Join(status.SANs, `,`),
+			status.Issuer,
+			status.Serial,
+			formatStatusTime(status.NotBefore),
+			formatStatusTime(status.NotAfter),
+			status.DaysLeft,
+			key,
+			status.KeyMatch,
+			chainStatus,
+			status.Revocation,
+			status.NeedSync,
+			status.Error,
+		)
+	}
+
+	return table.Flush()
+}
+
+func formatStatusTime(t time.Time) string {
+	if t.IsZero() {
+		return ``
+	}
+	return t.Format(`2006-01-02 15:04:05 MST`)
+}
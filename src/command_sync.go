@@ -0,0 +1,32 @@
+package main
+
+import (
+	"crypto/rsa"
+	"errors"
+	"ssl/config"
+)
+
+func newSyncCommand() *command {
+	return &command{
+		description: `copy current certificate bundle from main save format to the others`,
+		run: func(appConfig config.ConfigInterface, args []string) (err error) {
+			if len(args) > 0 {
+				return errors.New(`unexpected arguments passed`)
+			}
+
+			bundleManager, err := GenerateMultiBundleManagerFromFormatsSlice[*rsa.PrivateKey](appConfig.GetSaveFormats())
+			if err != nil {
+				return
+			}
+
+			err = bundleManager.Sync()
+			if err != nil {
+				return
+			}
+
+			logger.Infof(`save formats are in sync`)
+
+			return
+		},
+	}
+}
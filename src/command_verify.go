@@ -0,0 +1,42 @@
+package main
+
+import (
+	"crypto/rsa"
+	"errors"
+	"ssl/config"
+)
+
+func newVerifyCommand() *command {
+	return &command{
+		description: `check that configured endpoints serve current certificate`,
+		run: func(appConfig config.ConfigInterface, args []string) (err error) {
+			if len(args) > 0 {
+				return errors.New(`unexpected arguments passed`)
+			}
+
+			endpoints := appConfig.GetVerifyEndpoints()
+			if len(endpoints) < 1 {
+				return errors.New(`no verify endpoints configured`)
+			}
+
+			bundleManager, err := GenerateMultiBundleManagerFromFormatsSlice[*rsa.PrivateKey](appConfig.GetSaveFormats())
+			if err != nil {
+				return
+			}
+
+			expected, err := getExpectedServedChain(bundleManager)
+			if err != nil {
+				return
+			}
+
+			err = getEndpointsError(appConfig, endpoints, expected)
+			if err != nil {
+				return
+			}
+
+			logger.Infof(`served certificate verified on %d endpoint(s)`, len(endpoints))
+
+			return
+		},
+	}
+}
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"errors"
+	"net/url"
+)
+
+// issuerRegistryFilename is kept next to account key, it tells which CA issued every certificate
+const issuerRegistryFilename = `issuers.json`
+
+type externalAccountBinding struct {
+	KeyID string `json:"keyId"`
+	// HMACKey is base64url encoded as given by CA
+	HMACKey string `json:"hmacKey" secret:"true"`
+}
+
+// acmeProvider is one CA of the failover list, providers are tried in order
+type acmeProvider struct {
+	Name               string                  `json:"name"`
+	DirectoryURL       string                  `json:"directoryUrl"`
+	AccountKeyFilename string                  `json:"accountKeyFilename"`
+	EAB                *externalAccountBinding `json:"eab"`
+	// CAAIdentities are issuer domain names of the CA for preflight, preflight caaIdentities are used if empty
+	CAAIdentities []string `json:"caaIdentities"`
+}
+
+// ACMEProvider is a CA to order certificates from, empty DirectoryURL means Let's Encrypt
+type ACMEProvider struct {
+	Name               string
+	DirectoryURL       string
+	AccountKeyFilename string
+	EABKeyID           string
+	EABHMACKey         string
+	CAAIdentities      []string
+}
+
+func (c *Config) getACMEProvider(provider *acmeProvider) ACMEProvider {
+	result := ACMEProvider{
+		Name:               provider.Name,
+		DirectoryURL:       provider.DirectoryURL,
+		AccountKeyFilename: c.getAppFilename(provider.AccountKeyFilename),
+		CAAIdentities:      provider.CAAIdentities,
+	}
+	if provider.EAB != nil {
+		result.EABKeyID = provider.EAB.KeyID
+		result.EABHMACKey = provider.EAB.HMACKey
+	}
+	if len(result.CAAIdentities) < 1 {
+		result.CAAIdentities = c.Preflight.CAAIdentities
+	}
+	return result
+}
+
+func (p *acmeProvider) validate() (errs []error) {
+	if p.Name == `` {
+		errs = append(errs, errors.New(`acme provider name is not set`))
+	}
+	directoryURL, err := url.Parse(p.DirectoryURL)
+	if err != nil || directoryURL.Scheme != `https` || directoryURL.Host == `` {
+		errs = append(errs, errors.New(`acme provider "`+p.Name+`" directoryUrl "`+p.DirectoryURL+`" is not a https URL`))
+	}
+	if p.AccountKeyFilename == `` {
+		errs = append(errs, errors.New(`acme provider "`+p.Name+`" accountKeyFilename is not set`))
+	}
+	if p.EAB != nil && (p.EAB.KeyID == ``) != (p.EAB.HMACKey == ``) {
+		errs = append(errs, errors.New(`acme provider "`+p.Name+`" eab needs both keyId and hmacKey`))
+	}
+	return
+}
@@ -3,26 +3,65 @@ package config
 import (
 	"encoding/json"
 	"path/filepath"
+	"ssl/endpoint"
+	"ssl/hooks"
+	loglib "ssl/logger"
+	"ssl/notify"
+	"ssl/preflight"
+	"ssl/ratelimit"
+	"ssl/vault"
+	"time"
 )
 
+// defaultRenewBefore is used if no renewal threshold is configured
+const defaultRenewBefore = 30 * 24 * time.Hour
+
 type Config struct {
-	Env                string        `json:"env"`
-	Email              string        `json:"email"`
-	Domains            []string      `json:"domains"`
-	Port               uint16        `json:"port"`
-	KeyLength          uint16        `json:"keyLength"`
-	CertDaysLeftMin    uint8         `json:"certDaysLeftMin"`
-	UseStaging         bool          `json:"useStaging"`
-	AppPath            string        `json:"appPath"`
-	AccountKeyFilename string        `json:"accountKeyFilename"`
-	SaveFormats        []*saveFormat `json:"saveFormats"`
+	Env                string              `json:"env"`
+	Name               string              `json:"name"`
+	Email              string              `json:"email"`
+	Domains            []string            `json:"domains"`
+	Port               uint16              `json:"port"`
+	KeyLength          uint16              `json:"keyLength"`
+	CertDaysLeftMin    uint8               `json:"certDaysLeftMin"`
+	RenewBefore        Duration            `json:"renewBefore"`
+	RenewAt            float64             `json:"renewAt"`
+	UseStaging         bool                `json:"useStaging"`
+	AppPath            string              `json:"appPath"`
+	AccountKeyFilename string              `json:"accountKeyFilename"`
+	SaveFormats        []*saveFormat       `json:"saveFormats"`
+	Daemon             *daemon             `json:"daemon"`
+	Metrics            *metrics            `json:"metrics"`
+	Hooks              *hookSettings       `json:"hooks"`
+	Notifications      *notifications      `json:"notifications"`
+	Log                *logSettings        `json:"log"`
+	Revocation         *revocationSettings `json:"revocation"`
+	Preflight          *preflightSettings  `json:"preflight"`
+	RateLimits         *rateLimits         `json:"rateLimits"`
+	Verify             *verifySettings     `json:"verify"`
+	LocalCA            *localCA            `json:"localCA"`
+	Vault              *vaultSettings      `json:"vault"`
+	ACMEProviders      []*acmeProvider     `json:"acmeProviders"`
+	// sources keep where values came from, keys are json paths
+	sources map[string]string
 }
 
 func NewConfig(env string, appPath string) *Config {
 	return &Config{
-		Env:        env,
-		UseStaging: true,
-		AppPath:    appPath,
+		Env:           env,
+		UseStaging:    true,
+		AppPath:       appPath,
+		Daemon:        newDaemon(),
+		Metrics:       newMetrics(),
+		Hooks:         newHookSettings(),
+		Notifications: newNotifications(),
+		Log:           newLogSettings(),
+		Revocation:    newRevocationSettings(),
+		Preflight:     newPreflightSettings(),
+		RateLimits:    newRateLimits(),
+		Verify:        newVerifySettings(),
+		LocalCA:       newLocalCA(),
+		Vault:         newVaultSettings(),
 	}
 }
 
@@ -34,6 +73,15 @@ func (c *Config) SetEnv(env string) {
 	c.Env = env
 }
 
+// GetName returns certificate name for hooks, first domain is used if name is not set
+func (c *Config) GetName() string {
+	if c.Name != `` || len(c.Domains) < 1 {
+		return c.Name
+	}
+
+	return c.Domains[0]
+}
+
 func (c *Config) GetEmail() string {
 	return c.Email
 }
@@ -52,8 +100,25 @@ func (c *Config) GetKeyLength() uint16 {
 	return c.KeyLength
 }
 
-func (c *Config) GetCertDaysLeftMin() int {
-	return int(c.CertDaysLeftMin)
+// GetRenewBefore returns time left before expiration when certificate is renewed, 0 if renewAt is used instead.
+// Legacy certDaysLeftMin or default is used if neither renewBefore nor renewAt is set.
+func (c *Config) GetRenewBefore() time.Duration {
+	if c.RenewBefore > 0 {
+		return time.Duration(c.RenewBefore)
+	}
+	if c.RenewAt > 0 {
+		return 0
+	}
+	if c.CertDaysLeftMin > 0 {
+		return time.Duration(c.CertDaysLeftMin) * 24 * time.Hour
+	}
+
+	return defaultRenewBefore
+}
+
+// GetRenewAt returns elapsed fraction of validity period when certificate is renewed, 0 if not set
+func (c *Config) GetRenewAt() float64 {
+	return c.RenewAt
 }
 
 func (c *Config) GetUseStaging() bool {
@@ -61,15 +126,20 @@ func (c *Config) GetUseStaging() bool {
 }
 
 func (c *Config) GetAccountKeyFilename() string {
-	if c.AccountKeyFilename == `` {
+	return c.getAppFilename(c.AccountKeyFilename)
+}
+
+// getAppFilename resolves relative filenames against application path
+func (c *Config) getAppFilename(filename string) string {
+	if filename == `` {
 		return ``
 	}
 
-	if filepath.IsAbs(c.AccountKeyFilename) {
-		return filepath.Clean(c.AccountKeyFilename)
+	if filepath.IsAbs(filename) {
+		return filepath.Clean(filename)
 	}
 
-	return filepath.Join(c.AppPath, c.AccountKeyFilename)
+	return filepath.Join(c.AppPath, filename)
 }
 
 func (c *Config) GetSaveFormats() []SaveFormat {
@@ -85,6 +155,179 @@ func (c *Config) GetSaveFormats() []SaveFormat {
 	return formats
 }
 
+func (c *Config) GetDaemonInterval() time.Duration {
+	return time.Duration(c.Daemon.Interval)
+}
+
+func (c *Config) GetDaemonJitter() time.Duration {
+	return time.Duration(c.Daemon.Jitter)
+}
+
+func (c *Config) GetDaemonBackoffMin() time.Duration {
+	return time.Duration(c.Daemon.BackoffMin)
+}
+
+func (c *Config) GetDaemonBackoffMax() time.Duration {
+	return time.Duration(c.Daemon.BackoffMax)
+}
+
+func (c *Config) GetMetricsListen() string {
+	return c.Metrics.Listen
+}
+
+func (c *Config) GetMetricsTextfile() string {
+	return GenerateFullFilename(c.AppPath, c.Metrics.Textfile)
+}
+
+func (c *Config) GetPreRenewHooks() []hooks.Hook {
+	return convertHooks(c.Hooks.PreRenew)
+}
+
+func (c *Config) GetPostRenewHooks() []hooks.Hook {
+	return convertHooks(c.Hooks.PostRenew)
+}
+
+func (c *Config) GetDeployHooks() []hooks.Hook {
+	return convertHooks(c.Hooks.Deploy)
+}
+
+func (c *Config) GetOnFailureHooks() []hooks.Hook {
+	return convertHooks(c.Hooks.OnFailure)
+}
+
+func (c *Config) GetNotificationSettings() ([]notify.WebhookSettings, []notify.SMTPSettings) {
+	return c.Notifications.getSettings()
+}
+
+func (c *Config) GetNotificationExpiringDays() int {
+	return int(c.Notifications.ExpiringDays)
+}
+
+func (c *Config) GetNotificationExpiringAfterFailures() int {
+	return int(c.Notifications.ExpiringAfterFailures)
+}
+
+func (c *Config) GetRenewalFailuresFilename() string {
+	return filepath.Join(filepath.Dir(c.GetAccountKeyFilename()), failuresFilename)
+}
+
+func (c *Config) GetLogLevel() string {
+	return c.Log.Level
+}
+
+func (c *Config) GetLogFormat() string {
+	return c.Log.Format
+}
+
+func (c *Config) GetLogSinkSettings() loglib.SinkSettings {
+	return c.Log.getSinkSettings()
+}
+
+func (c *Config) GetRevocationCheckEnabled() bool {
+	return c.Revocation.Enabled
+}
+
+func (c *Config) GetRevocationCheckTimeout() time.Duration {
+	return time.Duration(c.Revocation.Timeout)
+}
+
+func (c *Config) GetPreflightEnabled() bool {
+	return c.Preflight.Enabled
+}
+
+func (c *Config) GetPreflightSettings() preflight.Settings {
+	return c.Preflight.getSettings(c.Port)
+}
+
+func (c *Config) GetRateLimitsEnabled() bool {
+	return c.RateLimits.Enabled
+}
+
+func (c *Config) GetRateLimits() ratelimit.Limits {
+	return c.RateLimits.getLimits()
+}
+
+func (c *Config) GetRateLimitLedgerFilename() string {
+	return filepath.Join(filepath.Dir(c.GetAccountKeyFilename()), ledgerFilename)
+}
+
+func (c *Config) GetVerifyEndpoints() []endpoint.Endpoint {
+	defaultServerName := ``
+	if len(c.Domains) > 0 {
+		defaultServerName = c.Domains[0]
+	}
+	return c.Verify.getEndpoints(defaultServerName)
+}
+
+func (c *Config) GetVerifyTimeout() time.Duration {
+	return time.Duration(c.Verify.Timeout)
+}
+
+func (c *Config) GetVerifyReloadRetries() int {
+	return c.Verify.ReloadRetries
+}
+
+func (c *Config) GetVerifyRetryDelay() time.Duration {
+	return time.Duration(c.Verify.RetryDelay)
+}
+
+func (c *Config) GetLocalCAEnabled() bool {
+	return c.LocalCA.Enabled
+}
+
+func (c *Config) GetLocalCAKeyFilename() string {
+	return c.getAppFilename(c.LocalCA.KeyFilename)
+}
+
+func (c *Config) GetLocalCACertificateFilename() string {
+	return c.getAppFilename(c.LocalCA.CertificateFilename)
+}
+
+func (c *Config) GetLocalCACommonName() string {
+	return c.LocalCA.CommonName
+}
+
+func (c *Config) GetLocalCAValidity() time.Duration {
+	return time.Duration(c.LocalCA.Validity)
+}
+
+func (c *Config) GetLocalCACertificateValidity() time.Duration {
+	return time.Duration(c.LocalCA.CertificateValidity)
+}
+
+func (c *Config) GetVaultEnabled() bool {
+	return c.Vault.Enabled
+}
+
+func (c *Config) GetVaultSettings() vault.Settings {
+	return c.Vault.getSettings()
+}
+
+// GetACMEProviders returns CAs in failover order, Let's Encrypt chosen by useStaging is the only one if none are set
+func (c *Config) GetACMEProviders() []ACMEProvider {
+	if len(c.ACMEProviders) < 1 {
+		name := `production`
+		if c.UseStaging {
+			name = `staging`
+		}
+		return []ACMEProvider{{
+			Name:               name,
+			AccountKeyFilename: c.GetAccountKeyFilename(),
+			CAAIdentities:      c.Preflight.CAAIdentities,
+		}}
+	}
+
+	providers := make([]ACMEProvider, 0, len(c.ACMEProviders))
+	for _, provider := range c.ACMEProviders {
+		providers = append(providers, c.getACMEProvider(provider))
+	}
+	return providers
+}
+
+func (c *Config) GetIssuerRegistryFilename() string {
+	return filepath.Join(filepath.Dir(c.GetAccountKeyFilename()), issuerRegistryFilename)
+}
+
 func (c *Config) GetAppPath() string {
 	return c.AppPath
 }
@@ -101,8 +344,14 @@ func (c *Config) updateFormatFolders() {
 	}
 }
 
+// String masks secret fields, so config can be logged safely
 func (c *Config) String() string {
-	jsonBytes, err := json.MarshalIndent(c, ``, `  `)
+	masked, err := c.getMaskedCopy()
+	if err != nil {
+		return err.Error()
+	}
+
+	jsonBytes, err := json.MarshalIndent(masked, ``, `  `)
 	if err != nil {
 		return err.Error()
 	}
@@ -116,7 +365,20 @@ func (c *Config) Validate() (errs []error) {
 	errs = append(errs, c.validateDomains()...)
 	errs = append(errs, c.validatePort()...)
 	errs = append(errs, c.validateKeyLength()...)
+	errs = append(errs, c.validateRenewalThreshold()...)
 	errs = append(errs, c.validateAccountKeyFilename()...)
 	errs = append(errs, c.validateSaveFormats()...)
+	errs = append(errs, c.validateDaemon()...)
+	errs = append(errs, c.validateMetrics()...)
+	errs = append(errs, c.validateHooks()...)
+	errs = append(errs, c.validateNotifications()...)
+	errs = append(errs, c.validateLog()...)
+	errs = append(errs, c.validateRevocation()...)
+	errs = append(errs, c.validatePreflight()...)
+	errs = append(errs, c.validateRateLimits()...)
+	errs = append(errs, c.validateVerify()...)
+	errs = append(errs, c.validateLocalCA()...)
+	errs = append(errs, c.validateVault()...)
+	errs = append(errs, c.validateACMEProviders()...)
 	return
 }
@@ -74,6 +74,19 @@ func (c *Config) validateKeyLength() (errs []error) {
 	return
 }
 
+func (c *Config) validateRenewalThreshold() (errs []error) {
+	if c.RenewAt < 0 || c.RenewAt >= 1 {
+		errs = append(errs, errors.New(`renewAt must be a fraction of validity period between 0 and 1`))
+	}
+	if c.RenewBefore < 0 {
+		errs = append(errs, errors.New(`renewBefore must not be negative`))
+	}
+	if c.RenewAt > 0 && c.RenewBefore > 0 {
+		errs = append(errs, errors.New(`only one of renewBefore and renewAt can be set`))
+	}
+	return
+}
+
 func (c *Config) validateAccountKeyFilename() (errs []error) {
 	if c.AccountKeyFilename == `` {
 		errs = append(errs, errors.New(`no account key passed`))
@@ -113,3 +126,151 @@ func (c *Config) validateSaveFormats() (errs []error) {
 
 	return
 }
+
+func (c *Config) validateDaemon() (errs []error) {
+	if c.Daemon == nil {
+		errs = append(errs, errors.New(`daemon settings are not set`))
+		return
+	}
+	return c.Daemon.validate()
+}
+
+func (c *Config) validateHooks() (errs []error) {
+	if c.Hooks == nil {
+		errs = append(errs, errors.New(`hook settings are not set`))
+		return
+	}
+	return c.Hooks.validate()
+}
+
+func (c *Config) validateNotifications() (errs []error) {
+	if c.Notifications == nil {
+		errs = append(errs, errors.New(`notification settings are not set`))
+		return
+	}
+	return c.Notifications.validate()
+}
+
+func (c *Config) validateLog() (errs []error) {
+	if c.Log == nil {
+		errs = append(errs, errors.New(`log settings are not set`))
+		return
+	}
+	return c.Log.validate()
+}
+
+func (c *Config) validateRevocation() (errs []error) {
+	if c.Revocation == nil {
+		errs = append(errs, errors.New(`revocation settings are not set`))
+		return
+	}
+	return c.Revocation.validate()
+}
+
+func (c *Config) validatePreflight() (errs []error) {
+	if c.Preflight == nil {
+		errs = append(errs, errors.New(`preflight settings are not set`))
+		return
+	}
+	return c.Preflight.validate()
+}
+
+func (c *Config) validateRateLimits() (errs []error) {
+	if c.RateLimits == nil {
+		errs = append(errs, errors.New(`rate limit settings are not set`))
+		return
+	}
+	return c.RateLimits.validate()
+}
+
+func (c *Config) validateVerify() (errs []error) {
+	if c.Verify == nil {
+		errs = append(errs, errors.New(`verify settings are not set`))
+		return
+	}
+	return c.Verify.validate()
+}
+
+func (c *Config) validateLocalCA() (errs []error) {
+	if c.LocalCA == nil {
+		errs = append(errs, errors.New(`local CA settings are not set`))
+		return
+	}
+	if !c.LocalCA.Enabled {
+		return
+	}
+
+	errs = c.LocalCA.validate()
+	for _, filename := range []string{c.GetLocalCAKeyFilename(), c.GetLocalCACertificateFilename()} {
+		if filename == `` {
+			continue
+		}
+		path := filepath.Dir(filename)
+		exists, _ := common.DirectoryExists(path)
+		if !exists {
+			errs = append(errs, errors.New(fmt.Sprintf(`folder "%s" does not exist`, path)))
+		}
+	}
+
+	return
+}
+
+func (c *Config) validateVault() (errs []error) {
+	if c.Vault == nil {
+		errs = append(errs, errors.New(`vault settings are not set`))
+		return
+	}
+	if !c.Vault.Enabled {
+		return
+	}
+	if c.LocalCA != nil && c.LocalCA.Enabled {
+		errs = append(errs, errors.New(`only one of localCA and vault can be enabled`))
+	}
+	return append(errs, c.Vault.validate()...)
+}
+
+func (c *Config) validateACMEProviders() (errs []error) {
+	names := make(map[string]bool)
+	for _, provider := range c.ACMEProviders {
+		if provider == nil {
+			errs = append(errs, errors.New(`acme provider is empty`))
+			continue
+		}
+		if names[provider.Name] {
+			errs = append(errs, errors.New(fmt.Sprintf(`acme provider "%s" is duplicated`, provider.Name)))
+		}
+		names[provider.Name] = true
+
+		errs = append(errs, provider.validate()...)
+		if provider.AccountKeyFilename == `` {
+			continue
+		}
+		path := filepath.Dir(c.getAppFilename(provider.AccountKeyFilename))
+		exists, _ := common.DirectoryExists(path)
+		if !exists {
+			errs = append(errs, errors.New(fmt.Sprintf(`folder "%s" does not exist`, path)))
+		}
+	}
+
+	return
+}
+
+func (c *Config) validateMetrics() (errs []error) {
+	if c.Metrics == nil {
+		errs = append(errs, errors.New(`metrics settings are not set`))
+		return
+	}
+
+	if c.Metrics.Textfile == `` {
+		return
+	}
+
+	path := filepath.Dir(c.GetMetricsTextfile())
+
+	exists, _ := common.DirectoryExists(path)
+	if !exists {
+		errs = append(errs, errors.New(fmt.Sprintf(`folder "%s" does not exist`, path)))
+	}
+
+	return
+}
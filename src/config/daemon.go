@@ -0,0 +1,45 @@
+package config
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	defaultDaemonInterval   = 12 * time.Hour
+	defaultDaemonJitter     = time.Hour
+	defaultDaemonBackoffMin = time.Minute
+	defaultDaemonBackoffMax = 6 * time.Hour
+)
+
+type daemon struct {
+	Interval   Duration `json:"interval"`
+	Jitter     Duration `json:"jitter"`
+	BackoffMin Duration `json:"backoffMin"`
+	BackoffMax Duration `json:"backoffMax"`
+}
+
+func newDaemon() *daemon {
+	return &daemon{
+		Interval:   Duration(defaultDaemonInterval),
+		Jitter:     Duration(defaultDaemonJitter),
+		BackoffMin: Duration(defaultDaemonBackoffMin),
+		BackoffMax: Duration(defaultDaemonBackoffMax),
+	}
+}
+
+func (d *daemon) validate() (errs []error) {
+	if d.Interval <= 0 {
+		errs = append(errs, errors.New(`daemon interval must be positive`))
+	}
+	if d.Jitter < 0 {
+		errs = append(errs, errors.New(`daemon jitter must not be negative`))
+	}
+	if d.BackoffMin <= 0 {
+		errs = append(errs, errors.New(`daemon minimal backoff must be positive`))
+	}
+	if d.BackoffMax < d.BackoffMin {
+		errs = append(errs, errors.New(`daemon maximal backoff must not be less than minimal one`))
+	}
+	return
+}
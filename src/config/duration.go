@@ -0,0 +1,31 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// Duration is time.Duration written in config files as a string like "12h" or "90m"
+type Duration time.Duration
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var raw string
+	err := json.Unmarshal(data, &raw)
+	if err != nil {
+		return errors.New(`duration should be a string like "12h"`)
+	}
+
+	duration, err := time.ParseDuration(raw)
+	if err != nil {
+		return err
+	}
+
+	*d = Duration(duration)
+
+	return nil
+}
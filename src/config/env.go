@@ -0,0 +1,47 @@
+package config
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+const envOverridePrefix = `SSL_`
+
+// envOverrideExcluded paths are chosen before files are read, so they can not be overridden after import
+var envOverrideExcluded = map[string]bool{
+	`env`:     true,
+	`appPath`: true,
+}
+
+// applyEnvOverrides sets values from variables like SSL_EMAIL or SSL_SAVEFORMATS_0_FOLDER.
+// Variables not matching any config field are skipped, as SSL_ prefix is shared with other tools.
+func (c *Config) applyEnvOverrides(environment []string) (errs []error) {
+	sort.Strings(environment)
+
+	for _, variable := range environment {
+		name, value, found := strings.Cut(variable, `=`)
+		if !found || !strings.HasPrefix(name, envOverridePrefix) {
+			continue
+		}
+
+		path := strings.ReplaceAll(strings.TrimPrefix(name, envOverridePrefix), `_`, pathSeparator)
+		canonical, exists := canonicalizePath(reflect.TypeOf(c), path)
+		if !exists || envOverrideExcluded[strings.Split(canonical, pathSeparator)[0]] {
+			logger.Debugf(`variable "%s" does not match any config field`, name)
+			continue
+		}
+
+		err := setValueByPath(c, canonical, value)
+		if err != nil {
+			errs = append(errs, errors.New(`variable "`+name+`": `+err.Error()))
+			continue
+		}
+
+		c.recordSource(canonical, envSource(name))
+		logger.Infof(`value "%s" set from variable "%s"`, canonical, name)
+	}
+
+	return
+}
@@ -0,0 +1,76 @@
+package config
+
+import (
+	"testing"
+)
+
+func getExplainedSource(conf *Config, path string) string {
+	for _, value := range conf.Explain() {
+		if value.Path == path {
+			return value.Source
+		}
+	}
+	return ``
+}
+
+func TestConfig_ApplyEnvOverrides(t *testing.T) {
+	folder := writeConfigFiles(t, map[string]string{
+		`config.json`: `{"env": "dev", "email": "file@example.com", "domains": ["a.com", "b.com", "c.com"], "saveFormats": [{"folder": "certs"}]}`,
+	})
+
+	conf := NewConfig(``, folder)
+	err := importConfig(conf, folder)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	errs := conf.applyEnvOverrides([]string{
+		`SSL_DOMAINS=x.com,y.com`,
+		`SSL_SAVEFORMATS_0_FOLDER=/etc/ssl`,
+		`SSL_CERT_FILE=/etc/ssl/certs/ca.pem`,
+		`SSL_SAVEFORMATS_5_UNKNOWN=value`,
+		`SSL_ENV=prod`,
+	})
+	if len(errs) > 0 {
+		t.Fatal(errs[0])
+	}
+
+	if len(conf.Domains) != 2 || conf.Domains[0] != `x.com` {
+		t.Fatal(`list variable was not applied`)
+	}
+
+	if conf.SaveFormats[0].Folder != `/etc/ssl` {
+		t.Fatal(`nested variable was not applied`)
+	}
+
+	if len(conf.SaveFormats) != 1 || conf.Env != `dev` {
+		t.Fatal(`unrelated or excluded variables changed config`)
+	}
+
+	sources := map[string]string{
+		`email`:                `file:` + folder + `/config.json`,
+		`domains.0`:            `env:SSL_DOMAINS`,
+		`domains.1`:            `env:SSL_DOMAINS`,
+		`saveFormats.0.folder`: `env:SSL_SAVEFORMATS_0_FOLDER`,
+		`port`:                 SourceDefault,
+	}
+	for path, expected := range sources {
+		if source := getExplainedSource(conf, path); source != expected {
+			t.Fatalf(`source of "%s" is "%s", expected "%s"`, path, source, expected)
+		}
+	}
+
+	_ = conf.applyOverrides(map[string]string{`email`: `flag@example.com`})
+	if getExplainedSource(conf, `email`) != SourceFlag {
+		t.Fatal(`flag source was not recorded`)
+	}
+}
+
+func TestConfig_ApplyEnvOverrides_InvalidValue(t *testing.T) {
+	conf := NewConfig(`dev`, t.TempDir())
+
+	errs := conf.applyEnvOverrides([]string{`SSL_PORT=http`})
+	if len(errs) != 1 {
+		t.Fatal(`invalid value was accepted`)
+	}
+}
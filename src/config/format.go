@@ -0,0 +1,80 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// configExtensions are imported in this order inside every layer, so later formats override earlier ones
+var configExtensions = []string{`json`, `yaml`, `yml`, `toml`}
+
+// convertToJSON turns yaml and toml documents into json, so every format is merged by json.Unmarshal the same way
+func convertToJSON(extension string, raw []byte) (data []byte, err error) {
+	var document any
+
+	switch extension {
+	case `json`:
+		return raw, nil
+	case `yaml`, `yml`:
+		err = yaml.Unmarshal(raw, &document)
+	case `toml`:
+		document = make(map[string]any)
+		err = toml.Unmarshal(raw, &document)
+	default:
+		err = errors.New(`unsupported config file extension "` + extension + `"`)
+	}
+	if err != nil {
+		return
+	}
+
+	document, err = normalizeDocument(document)
+	if err != nil {
+		return
+	}
+
+	// empty yaml document means no values
+	if document == nil {
+		document = make(map[string]any)
+	}
+
+	return json.Marshal(document)
+}
+
+// normalizeDocument converts yaml maps with non-string keys, which json can not encode
+func normalizeDocument(document any) (any, error) {
+	switch typed := document.(type) {
+	case map[string]any:
+		for key, value := range typed {
+			normalized, err := normalizeDocument(value)
+			if err != nil {
+				return nil, err
+			}
+			typed[key] = normalized
+		}
+		return typed, nil
+	case map[any]any:
+		converted := make(map[string]any, len(typed))
+		for key, value := range typed {
+			normalized, err := normalizeDocument(value)
+			if err != nil {
+				return nil, err
+			}
+			converted[fmt.Sprint(key)] = normalized
+		}
+		return converted, nil
+	case []any:
+		for i, value := range typed {
+			normalized, err := normalizeDocument(value)
+			if err != nil {
+				return nil, err
+			}
+			typed[i] = normalized
+		}
+		return typed, nil
+	}
+
+	return document, nil
+}
@@ -0,0 +1,72 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeConfigFiles(t *testing.T, files map[string]string) string {
+	folder := t.TempDir()
+	for name, content := range files {
+		err := os.WriteFile(filepath.Join(folder, name), []byte(content), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return folder
+}
+
+func TestImportConfig_Formats(t *testing.T) {
+	folder := writeConfigFiles(t, map[string]string{
+		`config.json`: `{"env": "dev", "email": "json@example.com", "port": 80, "keyLength": 2048}`,
+		`config.yaml`: "# yaml overrides json in the same layer\nemail: yaml@example.com\n" +
+			"domains:\n  - example.com\n  - www.example.com\n" +
+			"daemon:\n  interval: 6h\n",
+		`config.local.yml`: "port: 8080\n",
+		`config.dev.toml`: "keyLength = 4096\n\n[daemon]\njitter = \"5m\"\n\n" +
+			"[[saveFormats]]\nfolder = \"certs\"\ncertificate = \"cert.pem\"\n",
+	})
+
+	conf := NewConfig(``, folder)
+	err := importConfig(conf, folder)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if conf.Email != `yaml@example.com` {
+		t.Fatalf(`yaml did not override json in the same layer, email is "%s"`, conf.Email)
+	}
+
+	if len(conf.Domains) != 2 || conf.Domains[1] != `www.example.com` {
+		t.Fatal(`yaml list was not imported`)
+	}
+
+	if conf.Port != 8080 || conf.KeyLength != 4096 {
+		t.Fatal(`later layers did not override earlier ones`)
+	}
+
+	if time.Duration(conf.Daemon.Interval) != 6*time.Hour || time.Duration(conf.Daemon.Jitter) != 5*time.Minute {
+		t.Fatal(`nested sections were not deep merged`)
+	}
+
+	if time.Duration(conf.Daemon.BackoffMax) != defaultDaemonBackoffMax {
+		t.Fatal(`defaults of nested section were lost`)
+	}
+
+	if len(conf.SaveFormats) != 1 || conf.SaveFormats[0].CertificateFilename != `cert.pem` {
+		t.Fatal(`toml array of tables was not imported`)
+	}
+}
+
+func TestImportConfig_InvalidYAML(t *testing.T) {
+	folder := writeConfigFiles(t, map[string]string{
+		`config.yaml`: "env: dev\nemail: [unclosed\n",
+	})
+
+	err := importConfig(NewConfig(``, folder), folder)
+	if err == nil {
+		t.Fatal(`invalid yaml was accepted`)
+	}
+}
@@ -0,0 +1,66 @@
+package config
+
+import (
+	"errors"
+	"ssl/hooks"
+	"strings"
+	"time"
+)
+
+const defaultHookTimeout = 5 * time.Minute
+
+type hook struct {
+	Command string   `json:"command"`
+	Timeout Duration `json:"timeout"`
+}
+
+// hookSettings lists commands run around renewal. PostRenew hooks are run after every attempt which passed
+// preRenew ones, so a service stopped by preRenew is started again. Deploy hooks are run only for new certificate.
+type hookSettings struct {
+	PreRenew  []*hook `json:"preRenew"`
+	PostRenew []*hook `json:"postRenew"`
+	Deploy    []*hook `json:"deploy"`
+	OnFailure []*hook `json:"onFailure"`
+}
+
+func newHookSettings() *hookSettings {
+	return &hookSettings{}
+}
+
+func (h *hookSettings) validate() (errs []error) {
+	all := make([]*hook, 0)
+	all = append(all, h.PreRenew...)
+	all = append(all, h.PostRenew...)
+	all = append(all, h.Deploy...)
+	all = append(all, h.OnFailure...)
+
+	for _, item := range all {
+		if item == nil || strings.TrimSpace(item.Command) == `` {
+			errs = append(errs, errors.New(`hook command is empty`))
+			continue
+		}
+		if item.Timeout < 0 {
+			errs = append(errs, errors.New(`hook "`+item.Command+`" timeout must not be negative`))
+		}
+	}
+
+	return
+}
+
+func convertHooks(items ...[]*hook) (result []hooks.Hook) {
+	result = make([]hooks.Hook, 0)
+	for _, list := range items {
+		for _, item := range list {
+			if item == nil {
+				continue
+			}
+			timeout := time.Duration(item.Timeout)
+			if timeout == 0 {
+				timeout = defaultHookTimeout
+			}
+			result = append(result, hooks.Hook{Command: item.Command, Timeout: timeout})
+		}
+	}
+
+	return
+}
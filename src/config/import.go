@@ -16,6 +16,10 @@ type EnvStore interface {
 	SetAppPath(string)
 }
 
+type sourceRecorder interface {
+	recordDocumentSources(data []byte, source string)
+}
+
 func importConfig(config EnvStore, configPath string) error {
 	appPath := config.GetAppPath()
 
@@ -81,34 +85,22 @@ func importConfig(config EnvStore, configPath string) error {
 	return nil
 }
 
-func generateBasicConfigFileNames() [2]string {
-	var filenames [2]string
-
-	point := `.`
-	config := `config`
-	local := `local`
-	extension := `json`
-
-	components := []string{config, extension}
-	filenames[0] = strings.Join(components, point)
-	components = append(components[:1], local, components[1])
-	filenames[1] = strings.Join(components, point)
-
-	return filenames
+func generateBasicConfigFileNames() []string {
+	return generateConfigFileNames(`config`, `config.local`)
 }
 
-func generateEnvConfigFileNames(env string) [2]string {
-	var filenames [2]string
-
-	point := `.`
-	config := `config`
-	local := `local`
-	extension := `json`
+func generateEnvConfigFileNames(env string) []string {
+	return generateConfigFileNames(`config.`+env, `config.`+env+`.local`)
+}
 
-	components := []string{config, env, extension}
-	filenames[0] = strings.Join(components, point)
-	components = append(components[:2], local, components[2])
-	filenames[1] = strings.Join(components, point)
+// generateConfigFileNames keeps layer order, every layer lists all supported extensions
+func generateConfigFileNames(layers ...string) []string {
+	filenames := make([]string, 0, len(layers)*len(configExtensions))
+	for _, layer := range layers {
+		for _, extension := range configExtensions {
+			filenames = append(filenames, layer+`.`+extension)
+		}
+	}
 
 	return filenames
 }
@@ -122,16 +114,27 @@ func importFileToConfig(config any, path string) (imported bool, err error) {
 	if err != nil {
 		if errors.Is(err, os.ErrNotExist) {
 			err = nil
-			logger.Infof(`file "%s" does not exist`, path)
+			logger.Debugf(`file "%s" does not exist`, path)
 		}
 		return
 	}
 
+	raw, err = convertToJSON(strings.TrimPrefix(filepath.Ext(path), `.`), raw)
+	if err != nil {
+		err = errors.New(`file "` + path + `": ` + err.Error())
+		return
+	}
+
 	err = json.Unmarshal(raw, config)
 	if err != nil {
+		err = errors.New(`file "` + path + `": ` + err.Error())
 		return
 	}
 
+	if recorder, ok := config.(sourceRecorder); ok {
+		recorder.recordDocumentSources(raw, fileSource(path))
+	}
+
 	imported = true
 	logger.Infof(`file "%s" successfully merged`, path)
 	return
@@ -3,11 +3,15 @@ package config
 import (
 	"os"
 	"ssl/common"
+	loglib "ssl/logger"
 )
 
-func Initialize(envVarKeyEnvironment, envVarKeyConfigFolder string) (config ConfigInterface, errs []error) {
+func Initialize(envVarKeyEnvironment, envVarKeyConfigFolder string, overrides Overrides) (config ConfigInterface, errs []error) {
 	env, exists := os.LookupEnv(envVarKeyEnvironment)
-	if exists {
+	if overrides.Env != `` {
+		env = overrides.Env
+		logger.Infof(`environment is overridden to "%s"`, env)
+	} else if exists {
 		logger.Infof(`environment from "%s" is set to "%s"`, envVarKeyEnvironment, env)
 	} else {
 		logger.Infof(`variable "%s" is not set`, envVarKeyEnvironment)
@@ -22,7 +26,10 @@ func Initialize(envVarKeyEnvironment, envVarKeyConfigFolder string) (config Conf
 	conf := NewConfig(env, appPath)
 
 	configPath, exists := os.LookupEnv(envVarKeyConfigFolder)
-	if !exists {
+	if overrides.ConfigFolder != `` {
+		configPath = overrides.ConfigFolder
+		logger.Infof(`config files folder is overridden to "%s"`, configPath)
+	} else if !exists {
 		logger.Infof(`config files folder variable "%s" is not set. app root "%s" folder wil be used`, envVarKeyConfigFolder, appPath)
 	}
 
@@ -35,13 +42,29 @@ func Initialize(envVarKeyEnvironment, envVarKeyConfigFolder string) (config Conf
 	err = importConfig(conf, configPath)
 	if err != nil {
 		errs = append(errs, err)
-		logger.Infof("config final version:\n%s", conf)
+		logger.Debugf("config final version:\n%s", conf)
+		return
+	}
+
+	errs = conf.applyEnvOverrides(os.Environ())
+	if len(errs) > 0 {
+		return
+	}
+
+	errs = conf.applyOverrides(overrides.Values)
+	if len(errs) > 0 {
+		return
+	}
+
+	errs = conf.resolveSecrets()
+	loglib.RegisterSecrets(conf.getSecrets()...)
+	if len(errs) > 0 {
 		return
 	}
 
 	conf.updateFormatFolders()
 
-	logger.Infof("config final version:\n%s", conf)
+	logger.Debugf("config final version:\n%s", conf)
 	errs = conf.Validate()
 	if len(errs) < 1 {
 		config = conf
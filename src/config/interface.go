@@ -1,14 +1,66 @@
 package config
 
+import (
+	"ssl/endpoint"
+	"ssl/hooks"
+	loglib "ssl/logger"
+	"ssl/notify"
+	"ssl/preflight"
+	"ssl/ratelimit"
+	"ssl/vault"
+	"time"
+)
+
 type ConfigInterface interface {
 	GetEnv() string
+	GetName() string
 	GetEmail() string
 	GetPort() int
 	GetDomains() []string
 	GetKeyLength() uint16
-	GetCertDaysLeftMin() int
+	GetRenewBefore() time.Duration
+	GetRenewAt() float64
 	GetUseStaging() bool
 	GetAccountKeyFilename() string
 	GetSaveFormats() []SaveFormat
+	GetDaemonInterval() time.Duration
+	GetDaemonJitter() time.Duration
+	GetDaemonBackoffMin() time.Duration
+	GetDaemonBackoffMax() time.Duration
+	GetMetricsListen() string
+	GetMetricsTextfile() string
+	GetPreRenewHooks() []hooks.Hook
+	GetPostRenewHooks() []hooks.Hook
+	GetDeployHooks() []hooks.Hook
+	GetOnFailureHooks() []hooks.Hook
+	GetNotificationSettings() ([]notify.WebhookSettings, []notify.SMTPSettings)
+	GetNotificationExpiringDays() int
+	GetNotificationExpiringAfterFailures() int
+	GetRenewalFailuresFilename() string
+	GetLogLevel() string
+	GetLogFormat() string
+	GetLogSinkSettings() loglib.SinkSettings
+	GetRevocationCheckEnabled() bool
+	GetRevocationCheckTimeout() time.Duration
+	GetPreflightEnabled() bool
+	GetPreflightSettings() preflight.Settings
+	GetRateLimitsEnabled() bool
+	GetRateLimits() ratelimit.Limits
+	GetRateLimitLedgerFilename() string
+	GetVerifyEndpoints() []endpoint.Endpoint
+	GetVerifyTimeout() time.Duration
+	GetVerifyReloadRetries() int
+	GetVerifyRetryDelay() time.Duration
+	GetLocalCAEnabled() bool
+	GetLocalCAKeyFilename() string
+	GetLocalCACertificateFilename() string
+	GetLocalCACommonName() string
+	GetLocalCAValidity() time.Duration
+	GetLocalCACertificateValidity() time.Duration
+	GetVaultEnabled() bool
+	GetVaultSettings() vault.Settings
+	GetACMEProviders() []ACMEProvider
+	GetIssuerRegistryFilename() string
+	Explain() []*ExplainedValue
 	updateFormatFolders()
 }
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	defaultLocalCAKeyFilename         = `ca.key`
+	defaultLocalCACertificateFilename = `ca.pem`
+	defaultLocalCACommonName          = `ssl development CA`
+	defaultLocalCAValidity            = 10 * 365 * 24 * time.Hour
+	defaultLocalCACertificateValidity = 90 * 24 * time.Hour
+)
+
+// localCA issues certificates offline instead of ACME CA, CA files are generated on first use
+type localCA struct {
+	Enabled             bool     `json:"enabled"`
+	KeyFilename         string   `json:"keyFilename"`
+	CertificateFilename string   `json:"certificateFilename"`
+	CommonName          string   `json:"commonName"`
+	Validity            Duration `json:"validity"`
+	CertificateValidity Duration `json:"certificateValidity"`
+}
+
+func newLocalCA() *localCA {
+	return &localCA{
+		KeyFilename:         defaultLocalCAKeyFilename,
+		CertificateFilename: defaultLocalCACertificateFilename,
+		CommonName:          defaultLocalCACommonName,
+		Validity:            Duration(defaultLocalCAValidity),
+		CertificateValidity: Duration(defaultLocalCACertificateValidity),
+	}
+}
+
+func (l *localCA) validate() (errs []error) {
+	if l.KeyFilename == `` || l.CertificateFilename == `` {
+		errs = append(errs, errors.New(`local CA key and certificate filenames must be set`))
+	}
+	if l.CertificateValidity <= 0 || l.Validity <= l.CertificateValidity {
+		errs = append(errs, errors.New(`local CA validity must be longer than positive certificateValidity`))
+	}
+	return
+}
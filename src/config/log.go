@@ -0,0 +1,58 @@
+package config
+
+import (
+	loglib "ssl/logger"
+)
+
+const (
+	defaultLogLevel  = `info`
+	defaultLogFormat = `text`
+)
+
+type logSettings struct {
+	Level  string `json:"level"`
+	Format string `json:"format"`
+	// Sink is console, journald or syslog
+	Sink     string `json:"sink"`
+	Network  string `json:"network"`
+	Address  string `json:"address"`
+	Facility string `json:"facility"`
+	Tag      string `json:"tag"`
+}
+
+func newLogSettings() *logSettings {
+	return &logSettings{
+		Level:  defaultLogLevel,
+		Format: defaultLogFormat,
+		Sink:   loglib.SinkConsole,
+	}
+}
+
+func (l *logSettings) validate() (errs []error) {
+	err := loglib.ValidateLevel(l.Level)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	err = loglib.ValidateFormat(l.Format)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	err = loglib.ValidateSinkSettings(l.getSinkSettings())
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	return
+}
+
+func (l *logSettings) getSinkSettings() loglib.SinkSettings {
+	return loglib.SinkSettings{
+		Type:     l.Sink,
+		Network:  l.Network,
+		Address:  l.Address,
+		Facility: l.Facility,
+		Tag:      l.Tag,
+	}
+}
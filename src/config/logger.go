@@ -5,11 +5,16 @@ import (
 )
 
 type loggerInterface interface {
+	Debugf(format string, args ...interface{})
 	Infof(format string, args ...interface{})
 }
 
 type defaultLogger struct{}
 
+func (l *defaultLogger) Debugf(format string, args ...interface{}) {
+	fmt.Printf(format, args...)
+}
+
 func (l *defaultLogger) Infof(format string, args ...interface{}) {
 	fmt.Printf(format, args...)
 }
@@ -0,0 +1,10 @@
+package config
+
+type metrics struct {
+	Listen   string `json:"listen"`
+	Textfile string `json:"textfile"`
+}
+
+func newMetrics() *metrics {
+	return &metrics{}
+}
@@ -0,0 +1,129 @@
+package config
+
+import (
+	"errors"
+	"ssl/notify"
+)
+
+const (
+	defaultNotificationExpiringDays          = 7
+	defaultNotificationExpiringAfterFailures = 2
+)
+
+// failuresFilename is kept next to account key, it counts consecutive failed renewals
+const failuresFilename = `failures.json`
+
+type webhookNotification struct {
+	URL      string   `json:"url" secret:"true"`
+	Format   string   `json:"format"`
+	Template string   `json:"template"`
+	Events   []string `json:"events"`
+}
+
+type emailNotification struct {
+	Host string `json:"host"`
+	// Port is 587 if not set
+	Port     uint16   `json:"port"`
+	Username string   `json:"username"`
+	Password string   `json:"password" secret:"true"`
+	From     string   `json:"from"`
+	To       []string `json:"to"`
+	Subject  string   `json:"subject"`
+	Template string   `json:"template"`
+	Events   []string `json:"events"`
+}
+
+type notifications struct {
+	// ExpiringDays is how close to expiration failed renewal also sends "expiring" event
+	ExpiringDays uint16 `json:"expiringDays"`
+	// ExpiringAfterFailures is how many consecutive renewals must fail before "expiring" event is sent
+	ExpiringAfterFailures uint16                 `json:"expiringAfterFailures"`
+	Webhooks              []*webhookNotification `json:"webhooks"`
+	Emails                []*emailNotification   `json:"emails"`
+}
+
+func newNotifications() *notifications {
+	return &notifications{
+		ExpiringDays:          defaultNotificationExpiringDays,
+		ExpiringAfterFailures: defaultNotificationExpiringAfterFailures,
+	}
+}
+
+func (n *notifications) validate() (errs []error) {
+	webhooks, emails := n.getSettings()
+
+	if n.ExpiringAfterFailures < 1 {
+		errs = append(errs, errors.New(`notifications expiringAfterFailures must be at least 1`))
+	}
+
+	for _, settings := range webhooks {
+		if settings.URL == `` {
+			errs = append(errs, errors.New(`webhook url is empty`))
+			continue
+		}
+		_, err := notify.NewWebhook(settings)
+		if err != nil {
+			errs = append(errs, errors.New(`webhook "`+settings.URL+`": `+err.Error()))
+		}
+		errs = append(errs, validateNotificationEvents(settings.Events)...)
+	}
+
+	for _, settings := range emails {
+		_, err := notify.NewSMTP(settings)
+		if err != nil {
+			errs = append(errs, errors.New(`email notification: `+err.Error()))
+		}
+		errs = append(errs, validateNotificationEvents(settings.Events)...)
+	}
+
+	return
+}
+
+func validateNotificationEvents(events []notify.Event) (errs []error) {
+	for _, event := range events {
+		if !notify.IsKnownEvent(event) {
+			errs = append(errs, errors.New(`unknown notification event "`+string(event)+`"`))
+		}
+	}
+	return
+}
+
+func (n *notifications) getSettings() (webhooks []notify.WebhookSettings, emails []notify.SMTPSettings) {
+	for _, webhook := range n.Webhooks {
+		if webhook == nil {
+			continue
+		}
+		webhooks = append(webhooks, notify.WebhookSettings{
+			URL:      webhook.URL,
+			Format:   webhook.Format,
+			Template: webhook.Template,
+			Events:   convertNotificationEvents(webhook.Events),
+		})
+	}
+
+	for _, email := range n.Emails {
+		if email == nil {
+			continue
+		}
+		emails = append(emails, notify.SMTPSettings{
+			Host:     email.Host,
+			Port:     int(email.Port),
+			Username: email.Username,
+			Password: email.Password,
+			From:     email.From,
+			To:       email.To,
+			Subject:  email.Subject,
+			Template: email.Template,
+			Events:   convertNotificationEvents(email.Events),
+		})
+	}
+
+	return
+}
+
+func convertNotificationEvents(names []string) (events []notify.Event) {
+	for _, name := range names {
+		events = append(events, notify.Event(name))
+	}
+	return
+}
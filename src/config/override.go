@@ -0,0 +1,135 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const pathSeparator = `.`
+
+// Overrides are applied on top of config files. Values keys are json paths like "saveFormats.0.folder".
+type Overrides struct {
+	Env          string
+	ConfigFolder string
+	Values       map[string]string
+}
+
+func (c *Config) applyOverrides(values map[string]string) (errs []error) {
+	for path, value := range values {
+		err := setValueByPath(c, path, value)
+		if err != nil {
+			errs = append(errs, errors.New(`override "`+path+`": `+err.Error()))
+			continue
+		}
+		if canonical, found := canonicalizePath(reflect.TypeOf(c), path); found {
+			c.recordSource(canonical, SourceFlag)
+		}
+		logger.Infof(`value "%s" overridden`, path)
+	}
+
+	return
+}
+
+// setValueByPath walks target by json names (case-insensitive) and slice indexes, growing slices if needed
+func setValueByPath(target any, path string, value string) error {
+	current := reflect.ValueOf(target)
+	if current.Kind() != reflect.Pointer || current.IsNil() {
+		return errors.New(`target is not a pointer`)
+	}
+
+	for _, part := range strings.Split(path, pathSeparator) {
+		current = dereference(current)
+
+		switch current.Kind() {
+		case reflect.Struct:
+			field, found := findFieldByJSONName(current, part)
+			if !found {
+				return errors.New(`unknown field "` + part + `"`)
+			}
+			current = field
+		case reflect.Slice:
+			index, err := strconv.Atoi(part)
+			if err != nil || index < 0 {
+				return errors.New(`invalid index "` + part + `"`)
+			}
+			if index >= current.Len() {
+				current.Set(reflect.AppendSlice(current, reflect.MakeSlice(current.Type(), index-current.Len()+1, index-current.Len()+1)))
+			}
+			current = current.Index(index)
+		default:
+			return errors.New(`"` + part + `" can not be reached in scalar value`)
+		}
+	}
+
+	return setValue(current, value)
+}
+
+func dereference(value reflect.Value) reflect.Value {
+	for value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			value.Set(reflect.New(value.Type().Elem()))
+		}
+		value = value.Elem()
+	}
+
+	return value
+}
+
+func findFieldByJSONName(structValue reflect.Value, name string) (field reflect.Value, found bool) {
+	structField, found := findFieldTypeByJSONName(structValue.Type(), name)
+	if !found {
+		return
+	}
+
+	return structValue.FieldByIndex(structField.Index), true
+}
+
+func getJSONName(field reflect.StructField) string {
+	name := strings.Split(field.Tag.Get(`json`), `,`)[0]
+	if name == `` {
+		return field.Name
+	}
+
+	return name
+}
+
+// setValue accepts json literals, strings without quotes and comma separated string lists
+func setValue(target reflect.Value, value string) error {
+	if target.Kind() == reflect.Pointer {
+		target = dereference(target)
+	}
+
+	if target.Kind() == reflect.String {
+		target.SetString(value)
+		return nil
+	}
+
+	pointer := target.Addr().Interface()
+
+	err := json.Unmarshal([]byte(value), pointer)
+	if err == nil {
+		return nil
+	}
+
+	if target.Kind() == reflect.Slice && target.Type().Elem().Kind() == reflect.String {
+		list := make([]string, 0)
+		for _, item := range strings.Split(value, `,`) {
+			item = strings.TrimSpace(item)
+			if item != `` {
+				list = append(list, item)
+			}
+		}
+		target.Set(reflect.ValueOf(list).Convert(target.Type()))
+		return nil
+	}
+
+	quoted, _ := json.Marshal(value)
+	if json.Unmarshal(quoted, pointer) == nil {
+		return nil
+	}
+
+	return err
+}
@@ -0,0 +1,55 @@
+package config
+
+import (
+	"errors"
+	"net"
+	"ssl/preflight"
+	"time"
+)
+
+const defaultPreflightTimeout = 10 * time.Second
+
+// defaultCAAIdentity is issuer domain name of Let's Encrypt in CAA records
+const defaultCAAIdentity = `letsencrypt.org`
+
+type preflightSettings struct {
+	// Enabled checks DNS, CAA and HTTP-01 reachability of every domain before an order is placed
+	Enabled bool `json:"enabled"`
+	// Resolver is "host:port" of DNS server, system resolver is used if empty
+	Resolver      string   `json:"resolver"`
+	CAAIdentities []string `json:"caaIdentities"`
+	Timeout       Duration `json:"timeout"`
+}
+
+func newPreflightSettings() *preflightSettings {
+	return &preflightSettings{
+		Enabled:       true,
+		CAAIdentities: []string{defaultCAAIdentity},
+		Timeout:       Duration(defaultPreflightTimeout),
+	}
+}
+
+func (p *preflightSettings) getSettings(port uint16) preflight.Settings {
+	return preflight.Settings{
+		Resolver:      p.Resolver,
+		CAAIdentities: p.CAAIdentities,
+		ChallengePort: int(port),
+		Timeout:       time.Duration(p.Timeout),
+	}
+}
+
+func (p *preflightSettings) validate() (errs []error) {
+	if p.Timeout <= 0 {
+		errs = append(errs, errors.New(`preflight timeout must be positive`))
+	}
+	if p.Resolver != `` {
+		_, _, err := net.SplitHostPort(p.Resolver)
+		if err != nil {
+			errs = append(errs, errors.New(`preflight resolver "`+p.Resolver+`" is not host:port`))
+		}
+	}
+	if p.Enabled && len(p.CAAIdentities) < 1 {
+		errs = append(errs, errors.New(`preflight caaIdentities are not set`))
+	}
+	return
+}
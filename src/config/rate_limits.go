@@ -0,0 +1,66 @@
+package config
+
+import (
+	"ssl/ratelimit"
+	"time"
+)
+
+const week = 7 * 24 * time.Hour
+
+// ledgerFilename is kept next to account key, limits of CA are counted per account
+const ledgerFilename = `ledger.json`
+
+type rateLimit struct {
+	Count  int      `json:"count"`
+	Period Duration `json:"period"`
+}
+
+func (r *rateLimit) getLimit() ratelimit.Limit {
+	if r == nil {
+		return ratelimit.Limit{}
+	}
+	return ratelimit.Limit{Count: r.Count, Period: time.Duration(r.Period)}
+}
+
+// rateLimits default to Let's Encrypt limits
+type rateLimits struct {
+	Enabled               bool       `json:"enabled"`
+	CertificatesPerDomain *rateLimit `json:"certificatesPerDomain"`
+	DuplicateCertificates *rateLimit `json:"duplicateCertificates"`
+	FailedValidations     *rateLimit `json:"failedValidations"`
+}
+
+func newRateLimits() *rateLimits {
+	return &rateLimits{
+		Enabled:               true,
+		CertificatesPerDomain: &rateLimit{Count: 50, Period: Duration(week)},
+		DuplicateCertificates: &rateLimit{Count: 5, Period: Duration(week)},
+		FailedValidations:     &rateLimit{Count: 5, Period: Duration(time.Hour)},
+	}
+}
+
+func (r *rateLimits) getLimits() ratelimit.Limits {
+	return ratelimit.Limits{
+		CertificatesPerDomain: r.CertificatesPerDomain.getLimit(),
+		DuplicateCertificates: r.DuplicateCertificates.getLimit(),
+		FailedValidations:     r.FailedValidations.getLimit(),
+	}
+}
+
+func (r *rateLimits) validate() (errs []error) {
+	limits := r.getLimits()
+	for _, named := range []struct {
+		name  string
+		limit ratelimit.Limit
+	}{
+		{name: `certificatesPerDomain`, limit: limits.CertificatesPerDomain},
+		{name: `duplicateCertificates`, limit: limits.DuplicateCertificates},
+		{name: `failedValidations`, limit: limits.FailedValidations},
+	} {
+		err := named.limit.Validate(`rate limit ` + named.name)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+// sampleConfigFilename is the base config shipped with the app
+const sampleConfigFilename = `../../config/config.json`
+
+// importRenewalConfig layers local config over the shipped base config
+func importRenewalConfig(t *testing.T, local string) *Config {
+	base, err := os.ReadFile(sampleConfigFilename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	folder := writeConfigFiles(t, map[string]string{
+		`config.json`:       string(base),
+		`config.local.json`: local,
+	})
+
+	conf := NewConfig(``, folder)
+	err = importConfig(conf, folder)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	errs := conf.validateRenewalThreshold()
+	if len(errs) > 0 {
+		t.Fatal(errs[0])
+	}
+
+	return conf
+}
+
+func TestConfig_RenewAtOverride(t *testing.T) {
+	conf := importRenewalConfig(t, `{"renewAt": 0.66}`)
+
+	if conf.GetRenewAt() != 0.66 || conf.GetRenewBefore() != 0 {
+		t.Fatal(`renewAt of local config was not used alone`)
+	}
+}
+
+func TestConfig_CertDaysLeftMinOverride(t *testing.T) {
+	conf := importRenewalConfig(t, `{"certDaysLeftMin": 10}`)
+
+	if conf.GetRenewBefore() != 10*24*time.Hour || conf.GetRenewAt() != 0 {
+		t.Fatal(`legacy certDaysLeftMin was not honored`)
+	}
+}
+
+func TestConfig_RenewBeforeOverride(t *testing.T) {
+	conf := importRenewalConfig(t, `{"certDaysLeftMin": 10, "renewBefore": "48h"}`)
+
+	if conf.GetRenewBefore() != 48*time.Hour {
+		t.Fatal(`renewBefore should take precedence over certDaysLeftMin`)
+	}
+}
+
+func TestConfig_DefaultRenewBefore(t *testing.T) {
+	conf := importRenewalConfig(t, `{}`)
+
+	if conf.GetRenewBefore() != defaultRenewBefore {
+		t.Fatal(`default renewal threshold was not used`)
+	}
+}
@@ -0,0 +1,28 @@
+package config
+
+import (
+	"errors"
+	"time"
+)
+
+const defaultRevocationTimeout = 10 * time.Second
+
+type revocationSettings struct {
+	// Enabled makes OCSP (with CRL fallback) status a part of certificate validation
+	Enabled bool     `json:"enabled"`
+	Timeout Duration `json:"timeout"`
+}
+
+func newRevocationSettings() *revocationSettings {
+	return &revocationSettings{
+		Enabled: true,
+		Timeout: Duration(defaultRevocationTimeout),
+	}
+}
+
+func (r *revocationSettings) validate() (errs []error) {
+	if r.Timeout <= 0 {
+		errs = append(errs, errors.New(`revocation check timeout must be positive`))
+	}
+	return
+}
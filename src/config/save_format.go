@@ -30,6 +30,8 @@ type SaveFormat interface {
 	GetIntermediatePermissions() os.FileMode
 	GetIntermediatePattern() string
 	GetIntermediatePatternPermissions() os.FileMode
+	GetOCSPStapleFilename() string
+	GetOCSPStaplePermissions() os.FileMode
 }
 
 type saveFormat struct {
@@ -41,6 +43,7 @@ type saveFormat struct {
 	IntermediateFilename             string `json:"intermediate"`
 	IntermediatePattern              string `json:"intermediatePattern"`
 	CertificateChainFilename         string `json:"certificateChain"`
+	OCSPStapleFilename               string `json:"ocspStaple"`
 }
 
 func (s *saveFormat) GetAllInOneFilename() string {
@@ -99,6 +102,14 @@ func (s *saveFormat) GetIntermediatePatternPermissions() os.FileMode {
 	return defaultCertificatePermissions
 }
 
+func (s *saveFormat) GetOCSPStapleFilename() string {
+	return GenerateFullFilename(s.Folder, s.OCSPStapleFilename)
+}
+
+func (s *saveFormat) GetOCSPStaplePermissions() os.FileMode {
+	return defaultCertificatePermissions
+}
+
 func (s *saveFormat) Validate() (errs []error) {
 	path := filepath.Dir(s.GetPrivateKeyFilename())
 	if path != `` {
@@ -149,6 +160,13 @@ func (s *saveFormat) Validate() (errs []error) {
 			errs = append(errs, errors.New(fmt.Sprintf(`folder "%s" does not exist`, path)))
 		}
 	}
+	path = filepath.Dir(s.GetOCSPStapleFilename())
+	if path != `` {
+		exists, _ := common.DirectoryExists(path)
+		if !exists {
+			errs = append(errs, errors.New(fmt.Sprintf(`folder "%s" does not exist`, path)))
+		}
+	}
 
 	return
 }
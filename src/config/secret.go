@@ -0,0 +1,149 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const (
+	secretTag          = `secret`
+	secretMask         = `******`
+	secretFilePrefix   = `file:`
+	secretEnvPrefix    = `env:`
+	secretFileMaxBytes = 64 * 1024
+)
+
+// walkSecretFields calls fn for every string field tagged `secret:"true"`, nested structs and slices included.
+// Paths passed to fn are json paths like "notifications.emails.0.password".
+func walkSecretFields(value reflect.Value, path string, fn func(field reflect.Value, path string) error) (errs []error) {
+	for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return
+		}
+		value = value.Elem()
+	}
+
+	switch value.Kind() {
+	case reflect.Struct:
+		valueType := value.Type()
+		for i := 0; i < valueType.NumField(); i++ {
+			field := valueType.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+
+			fieldPath := joinPath(path, getJSONName(field))
+			if field.Tag.Get(secretTag) == `true` && field.Type.Kind() == reflect.String {
+				err := fn(value.Field(i), fieldPath)
+				if err != nil {
+					errs = append(errs, errors.New(`secret "`+fieldPath+`": `+err.Error()))
+				}
+				continue
+			}
+
+			errs = append(errs, walkSecretFields(value.Field(i), fieldPath, fn)...)
+		}
+	case reflect.Slice:
+		for i := 0; i < value.Len(); i++ {
+			errs = append(errs, walkSecretFields(value.Index(i), joinPath(path, strconv.Itoa(i)), fn)...)
+		}
+	}
+
+	return
+}
+
+func joinPath(path string, part string) string {
+	if path == `` {
+		return part
+	}
+	return path + pathSeparator + part
+}
+
+// resolveSecrets replaces "file:<path>" and "env:<name>" references with their values.
+// Relative files are looked up from app path.
+func (c *Config) resolveSecrets() []error {
+	return walkSecretFields(reflect.ValueOf(c), ``, func(field reflect.Value, path string) error {
+		value, err := resolveSecret(field.String(), c.AppPath)
+		if err != nil {
+			return err
+		}
+		field.SetString(value)
+		return nil
+	})
+}
+
+func resolveSecret(reference string, appPath string) (value string, err error) {
+	switch {
+	case strings.HasPrefix(reference, secretFilePrefix):
+		filename := GenerateFullFilename(appPath, strings.TrimPrefix(reference, secretFilePrefix))
+
+		var info os.FileInfo
+		info, err = os.Stat(filename)
+		if err != nil {
+			return
+		}
+		if info.Size() > secretFileMaxBytes {
+			err = errors.New(`file "` + filename + `" is too big for a secret`)
+			return
+		}
+
+		var data []byte
+		data, err = os.ReadFile(filename)
+		if err != nil {
+			return
+		}
+
+		value = strings.TrimRight(string(data), "\r\n")
+	case strings.HasPrefix(reference, secretEnvPrefix):
+		name := strings.TrimPrefix(reference, secretEnvPrefix)
+
+		var exists bool
+		value, exists = os.LookupEnv(name)
+		if !exists {
+			err = errors.New(`environment variable "` + name + `" is not set`)
+		}
+	default:
+		value = reference
+	}
+
+	return
+}
+
+// getSecrets returns values of all secret fields, so loggers can redact them
+func (c *Config) getSecrets() (secrets []string) {
+	_ = walkSecretFields(reflect.ValueOf(c), ``, func(field reflect.Value, path string) error {
+		if field.String() != `` {
+			secrets = append(secrets, field.String())
+		}
+		return nil
+	})
+
+	return
+}
+
+// getMaskedCopy returns deep copy of config with secret fields replaced by mask
+func (c *Config) getMaskedCopy() (masked *Config, err error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return
+	}
+
+	masked = &Config{}
+	err = json.Unmarshal(data, masked)
+	if err != nil {
+		return
+	}
+
+	_ = walkSecretFields(reflect.ValueOf(masked), ``, func(field reflect.Value, path string) error {
+		if field.String() != `` {
+			field.SetString(secretMask)
+		}
+		return nil
+	})
+
+	return
+}
@@ -0,0 +1,71 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newSecretTestConfig(appPath string, password string, url string) *Config {
+	conf := NewConfig(`dev`, appPath)
+	conf.Notifications.Emails = []*emailNotification{{Host: `localhost`, Password: password}}
+	conf.Notifications.Webhooks = []*webhookNotification{{URL: url}}
+	return conf
+}
+
+func TestConfig_ResolveSecrets(t *testing.T) {
+	appPath := t.TempDir()
+	err := os.WriteFile(filepath.Join(appPath, `smtp.secret`), []byte("from-file\n"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(`SSL_TEST_WEBHOOK`, `https://hooks.example.com/token`)
+
+	conf := newSecretTestConfig(appPath, `file:smtp.secret`, `env:SSL_TEST_WEBHOOK`)
+
+	errs := conf.resolveSecrets()
+	if len(errs) > 0 {
+		t.Fatal(errs[0])
+	}
+
+	if conf.Notifications.Emails[0].Password != `from-file` {
+		t.Fatal(`file reference was not resolved`)
+	}
+
+	if conf.Notifications.Webhooks[0].URL != `https://hooks.example.com/token` {
+		t.Fatal(`env reference was not resolved`)
+	}
+}
+
+func TestConfig_ResolveSecrets_Missing(t *testing.T) {
+	conf := newSecretTestConfig(t.TempDir(), `file:absent.secret`, `env:SSL_TEST_ABSENT_VARIABLE`)
+
+	errs := conf.resolveSecrets()
+	if len(errs) != 2 {
+		t.Fatalf(`expected 2 errors for missing references, got %d`, len(errs))
+	}
+
+	if !strings.Contains(errs[0].Error(), `notifications.webhooks.0.url`) {
+		t.Fatal(`error does not point to the field`)
+	}
+}
+
+func TestConfig_StringMasksSecrets(t *testing.T) {
+	conf := newSecretTestConfig(t.TempDir(), `smtp-password`, `https://hooks.example.com/token`)
+	conf.Email = `admin@example.com`
+
+	text := conf.String()
+
+	if strings.Contains(text, `smtp-password`) || strings.Contains(text, `hooks.example.com`) {
+		t.Fatalf(`secrets leaked to config dump: %s`, text)
+	}
+
+	if !strings.Contains(text, `admin@example.com`) {
+		t.Fatal(`regular fields are missing in config dump`)
+	}
+
+	if conf.Notifications.Emails[0].Password != `smtp-password` {
+		t.Fatal(`masking changed config itself`)
+	}
+}
@@ -0,0 +1,193 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	SourceDefault = `default`
+	SourceFlag    = `flag`
+)
+
+// ExplainedValue is final value of a config field and where it came from
+type ExplainedValue struct {
+	Path   string `json:"path"`
+	Value  string `json:"value"`
+	Source string `json:"source"`
+}
+
+func fileSource(path string) string {
+	return `file:` + path
+}
+
+func envSource(name string) string {
+	return `env:` + name
+}
+
+// recordSource remembers the source of path, values set earlier below this path are replaced
+func (c *Config) recordSource(path string, source string) {
+	if c.sources == nil {
+		c.sources = make(map[string]string)
+	}
+
+	for recorded := range c.sources {
+		if strings.HasPrefix(recorded, path+pathSeparator) {
+			delete(c.sources, recorded)
+		}
+	}
+	c.sources[path] = source
+}
+
+// recordDocumentSources walks json document merged into config, recording every leaf it sets.
+// Arrays replace previous values, objects are merged.
+func (c *Config) recordDocumentSources(data []byte, source string) {
+	var document any
+	if json.Unmarshal(data, &document) != nil {
+		return
+	}
+
+	c.recordDocumentValue(reflect.TypeOf(c), ``, document, source)
+}
+
+func (c *Config) recordDocumentValue(valueType reflect.Type, path string, value any, source string) {
+	for valueType.Kind() == reflect.Pointer {
+		valueType = valueType.Elem()
+	}
+
+	switch typed := value.(type) {
+	case map[string]any:
+		if valueType.Kind() != reflect.Struct {
+			c.recordSource(path, source)
+			return
+		}
+		for key, item := range typed {
+			field, found := findFieldTypeByJSONName(valueType, key)
+			if !found {
+				continue
+			}
+			c.recordDocumentValue(field.Type, joinPath(path, getJSONName(field)), item, source)
+		}
+	case []any:
+		c.recordSource(path, source)
+		if valueType.Kind() != reflect.Slice {
+			return
+		}
+		for i, item := range typed {
+			c.recordDocumentValue(valueType.Elem(), joinPath(path, strconv.Itoa(i)), item, source)
+		}
+	default:
+		if path != `` {
+			c.recordSource(path, source)
+		}
+	}
+}
+
+// getSource returns source of path or of its closest recorded parent
+func (c *Config) getSource(path string) string {
+	for {
+		if source, exists := c.sources[path]; exists {
+			return source
+		}
+
+		separator := strings.LastIndex(path, pathSeparator)
+		if separator < 0 {
+			return SourceDefault
+		}
+		path = path[:separator]
+	}
+}
+
+// Explain lists every set leaf value of config with its source, secrets are masked
+func (c *Config) Explain() (values []*ExplainedValue) {
+	secretPaths := make(map[string]bool)
+	_ = walkSecretFields(reflect.ValueOf(c), ``, func(field reflect.Value, path string) error {
+		secretPaths[path] = true
+		return nil
+	})
+
+	collectLeafValues(reflect.ValueOf(c), ``, func(path string, value string) {
+		if secretPaths[path] && value != `` {
+			value = secretMask
+		}
+		values = append(values, &ExplainedValue{Path: path, Value: value, Source: c.getSource(path)})
+	})
+
+	return
+}
+
+func collectLeafValues(value reflect.Value, path string, fn func(path string, value string)) {
+	for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return
+		}
+		value = value.Elem()
+	}
+
+	if value.Type() == reflect.TypeOf(Duration(0)) {
+		fn(path, time.Duration(value.Int()).String())
+		return
+	}
+
+	switch value.Kind() {
+	case reflect.Struct:
+		valueType := value.Type()
+		for i := 0; i < valueType.NumField(); i++ {
+			field := valueType.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			collectLeafValues(value.Field(i), joinPath(path, getJSONName(field)), fn)
+		}
+	case reflect.Slice:
+		for i := 0; i < value.Len(); i++ {
+			collectLeafValues(value.Index(i), joinPath(path, strconv.Itoa(i)), fn)
+		}
+	default:
+		fn(path, fmt.Sprint(value.Interface()))
+	}
+}
+
+// canonicalizePath checks path against config types without changing values and returns it with json names
+func canonicalizePath(valueType reflect.Type, path string) (canonical string, found bool) {
+	for _, part := range strings.Split(path, pathSeparator) {
+		for valueType.Kind() == reflect.Pointer {
+			valueType = valueType.Elem()
+		}
+
+		switch valueType.Kind() {
+		case reflect.Struct:
+			field, exists := findFieldTypeByJSONName(valueType, part)
+			if !exists {
+				return
+			}
+			canonical = joinPath(canonical, getJSONName(field))
+			valueType = field.Type
+		case reflect.Slice:
+			index, err := strconv.Atoi(part)
+			if err != nil || index < 0 {
+				return
+			}
+			canonical = joinPath(canonical, strconv.Itoa(index))
+			valueType = valueType.Elem()
+		default:
+			return
+		}
+	}
+
+	return canonical, true
+}
+
+func findFieldTypeByJSONName(structType reflect.Type, name string) (field reflect.StructField, found bool) {
+	for i := 0; i < structType.NumField(); i++ {
+		if structType.Field(i).IsExported() && strings.EqualFold(getJSONName(structType.Field(i)), name) {
+			return structType.Field(i), true
+		}
+	}
+
+	return
+}
@@ -0,0 +1,88 @@
+package config
+
+import (
+	"errors"
+	"net"
+	"net/url"
+	"ssl/vault"
+	"time"
+)
+
+const (
+	defaultVaultMount        = `pki`
+	defaultVaultAppRoleMount = `approle`
+	defaultVaultTimeout      = 30 * time.Second
+)
+
+type vaultAppRole struct {
+	Mount    string `json:"mount"`
+	RoleID   string `json:"roleId"`
+	SecretID string `json:"secretId" secret:"true"`
+}
+
+// vaultSettings make Vault PKI secrets engine the issuer instead of ACME CA
+type vaultSettings struct {
+	Enabled   bool   `json:"enabled"`
+	Address   string `json:"address"`
+	Namespace string `json:"namespace"`
+	Mount     string `json:"mount"`
+	Role      string `json:"role"`
+	// TTL of issued certificates, role default is used if empty
+	TTL      Duration `json:"ttl"`
+	AltNames []string `json:"altNames"`
+	IPSANs   []string `json:"ipSans"`
+	// Token takes precedence over AppRole
+	Token   string        `json:"token" secret:"true"`
+	AppRole *vaultAppRole `json:"appRole"`
+	Timeout Duration      `json:"timeout"`
+}
+
+func newVaultSettings() *vaultSettings {
+	return &vaultSettings{
+		Mount:   defaultVaultMount,
+		AppRole: &vaultAppRole{Mount: defaultVaultAppRoleMount},
+		Timeout: Duration(defaultVaultTimeout),
+	}
+}
+
+func (v *vaultSettings) getSettings() vault.Settings {
+	settings := vault.Settings{
+		Address:   v.Address,
+		Namespace: v.Namespace,
+		Mount:     v.Mount,
+		Role:      v.Role,
+		TTL:       time.Duration(v.TTL),
+		AltNames:  v.AltNames,
+		IPSANs:    v.IPSANs,
+		Token:     v.Token,
+		Timeout:   time.Duration(v.Timeout),
+	}
+	if v.AppRole != nil {
+		settings.AppRoleMount = v.AppRole.Mount
+		settings.RoleID = v.AppRole.RoleID
+		settings.SecretID = v.AppRole.SecretID
+	}
+	return settings
+}
+
+func (v *vaultSettings) validate() (errs []error) {
+	address, err := url.Parse(v.Address)
+	if err != nil || (address.Scheme != `http` && address.Scheme != `https`) || address.Host == `` {
+		errs = append(errs, errors.New(`vault address "`+v.Address+`" is not a http(s) URL`))
+	}
+	if v.Mount == `` || v.Role == `` {
+		errs = append(errs, errors.New(`vault mount and role must be set`))
+	}
+	if v.Token == `` && (v.AppRole == nil || v.AppRole.Mount == `` || v.AppRole.RoleID == `` || v.AppRole.SecretID == ``) {
+		errs = append(errs, errors.New(`vault token or appRole mount, roleId and secretId must be set`))
+	}
+	if v.TTL < 0 || v.Timeout <= 0 {
+		errs = append(errs, errors.New(`vault ttl must not be negative and timeout must be positive`))
+	}
+	for _, ip := range v.IPSANs {
+		if net.ParseIP(ip) == nil {
+			errs = append(errs, errors.New(`vault ipSans value "`+ip+`" is not an IP address`))
+		}
+	}
+	return
+}
@@ -0,0 +1,71 @@
+package config
+
+import (
+	"errors"
+	"ssl/endpoint"
+	"time"
+)
+
+const defaultVerifyTimeout = 10 * time.Second
+const defaultVerifyRetryDelay = 5 * time.Second
+
+type verifyEndpoint struct {
+	Address string `json:"address"`
+	// ServerName is sent as SNI, first domain is used if empty
+	ServerName string `json:"serverName"`
+}
+
+// verifySettings list endpoints which must serve the certificate after deploy
+type verifySettings struct {
+	Endpoints []*verifyEndpoint `json:"endpoints"`
+	Timeout   Duration          `json:"timeout"`
+	// ReloadRetries is how many times postRenew hooks are run again on mismatch
+	ReloadRetries int      `json:"reloadRetries"`
+	RetryDelay    Duration `json:"retryDelay"`
+}
+
+func newVerifySettings() *verifySettings {
+	return &verifySettings{
+		Endpoints:  make([]*verifyEndpoint, 0),
+		Timeout:    Duration(defaultVerifyTimeout),
+		RetryDelay: Duration(defaultVerifyRetryDelay),
+	}
+}
+
+func (v *verifySettings) getEndpoints(defaultServerName string) []endpoint.Endpoint {
+	endpoints := make([]endpoint.Endpoint, 0, len(v.Endpoints))
+	for _, item := range v.Endpoints {
+		if item == nil {
+			continue
+		}
+		serverName := item.ServerName
+		if serverName == `` {
+			serverName = defaultServerName
+		}
+		endpoints = append(endpoints, endpoint.Endpoint{Address: item.Address, ServerName: serverName})
+	}
+	return endpoints
+}
+
+func (v *verifySettings) validate() (errs []error) {
+	for _, item := range v.Endpoints {
+		if item == nil {
+			errs = append(errs, errors.New(`verify endpoint is empty`))
+			continue
+		}
+		err := endpoint.ValidateAddress(item.Address)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if v.Timeout <= 0 {
+		errs = append(errs, errors.New(`verify timeout must be positive`))
+	}
+	if v.ReloadRetries < 0 {
+		errs = append(errs, errors.New(`verify reloadRetries must not be negative`))
+	}
+	if v.RetryDelay < 0 {
+		errs = append(errs, errors.New(`verify retryDelay must not be negative`))
+	}
+	return
+}
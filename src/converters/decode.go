@@ -0,0 +1,107 @@
+package converters
+
+import (
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"software.sslmate.com/src/go-pkcs12"
+	"strings"
+)
+
+// DecodeKeysAndCertificates extracts private keys and certificates from PEM, DER or PKCS#12 encoded data.
+func DecodeKeysAndCertificates(data []byte, password string) (keys []crypto.PrivateKey, certificates []*x509.Certificate, err error) {
+	keys = make([]crypto.PrivateKey, 0)
+	certificates = make([]*x509.Certificate, 0)
+
+	if len(data) < 1 {
+		err = errors.New(`empty data passed`)
+		return
+	}
+
+	if containsPEM(data) {
+		return decodePEM(data)
+	}
+
+	certs, certsErr := x509.ParseCertificates(data)
+	if certsErr == nil && len(certs) > 0 {
+		certificates = append(certificates, certs...)
+		return
+	}
+
+	key, keyErr := decodeDERPrivateKey(data)
+	if keyErr == nil {
+		keys = append(keys, key)
+		return
+	}
+
+	key, certificate, caCertificates, err := pkcs12.DecodeChain(data, password)
+	if err != nil {
+		err = errors.New(`data is neither PEM, DER nor PKCS#12 (` + err.Error() + `)`)
+		return
+	}
+
+	if key != nil {
+		keys = append(keys, key)
+	}
+	if certificate != nil {
+		certificates = append(certificates, certificate)
+	}
+	certificates = append(certificates, caCertificates...)
+
+	return
+}
+
+func containsPEM(data []byte) bool {
+	block, _ := pem.Decode(data)
+	return block != nil
+}
+
+func decodePEM(data []byte) (keys []crypto.PrivateKey, certificates []*x509.Certificate, err error) {
+	keys = make([]crypto.PrivateKey, 0)
+	certificates = make([]*x509.Certificate, 0)
+
+	var block *pem.Block
+	var key crypto.PrivateKey
+	var certificate *x509.Certificate
+	left := data
+	for {
+		block, left = pem.Decode(left)
+		if block == nil {
+			break
+		}
+
+		switch {
+		case block.Type == `CERTIFICATE`:
+			certificate, err = x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return
+			}
+			certificates = append(certificates, certificate)
+		case strings.HasSuffix(block.Type, privateKeyType):
+			key, err = parsePrivateKey(block.Type, block.Bytes)
+			if err != nil {
+				return
+			}
+			keys = append(keys, key)
+		}
+	}
+
+	return
+}
+
+func decodeDERPrivateKey(data []byte) (key crypto.PrivateKey, err error) {
+	key, err = x509.ParsePKCS8PrivateKey(data)
+	if err == nil {
+		return
+	}
+
+	key, err = x509.ParsePKCS1PrivateKey(data)
+	if err == nil {
+		return
+	}
+
+	key, err = x509.ParseECPrivateKey(data)
+
+	return
+}
@@ -0,0 +1,112 @@
+package converters
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+)
+
+// newTestChain returns leaf key and leaf, root chain, small key keeps tests fast
+func newTestChain(t *testing.T) (key *rsa.PrivateKey, certificates []*x509.Certificate) {
+	rootKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err = rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	rootTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: `test root`},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	rootRaw, err := x509.CreateCertificate(rand.Reader, rootTemplate, rootTemplate, rootKey.Public(), rootKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root, err := x509.ParseCertificate(rootRaw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	leafTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: `example.com`},
+		DNSNames:     []string{`example.com`},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(time.Hour),
+	}
+	leafRaw, err := x509.CreateCertificate(rand.Reader, leafTemplate, root, key.Public(), rootKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := x509.ParseCertificate(leafRaw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return key, []*x509.Certificate{leaf, root}
+}
+
+func assertSameCertificates(t *testing.T, actual []*x509.Certificate, expected []*x509.Certificate) {
+	if len(actual) != len(expected) {
+		t.Fatalf(`%d certificates expected, got %d`, len(expected), len(actual))
+	}
+	for i := range expected {
+		if !bytes.Equal(actual[i].Raw, expected[i].Raw) {
+			t.Fatalf(`certificate %d differs`, i)
+		}
+	}
+}
+
+func assertSameKey(t *testing.T, actual any, expected *rsa.PrivateKey) {
+	key, ok := actual.(*rsa.PrivateKey)
+	if !ok || !key.Equal(expected) {
+		t.Fatal(`decoded key differs`)
+	}
+}
+
+func TestDecodeKeysAndCertificates_PEM(t *testing.T) {
+	key, certificates := newTestChain(t)
+
+	data := &bytes.Buffer{}
+	_ = pem.Encode(data, &pem.Block{Type: `RSA PRIVATE KEY`, Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	for _, certificate := range certificates {
+		_ = pem.Encode(data, &pem.Block{Type: `CERTIFICATE`, Bytes: certificate.Raw})
+	}
+
+	keys, decoded, err := DecodeKeysAndCertificates(data.Bytes(), ``)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 1 {
+		t.Fatal(`one key expected`)
+	}
+	assertSameKey(t, keys[0], key)
+	assertSameCertificates(t, decoded, certificates)
+}
+
+func TestDecodeKeysAndCertificates_Invalid(t *testing.T) {
+	_, _, err := DecodeKeysAndCertificates(nil, ``)
+	if err == nil {
+		t.Fatal(`empty data was accepted`)
+	}
+
+	_, _, err = DecodeKeysAndCertificates([]byte(`not a certificate`), ``)
+	if err == nil {
+		t.Fatal(`garbage was accepted`)
+	}
+}
@@ -0,0 +1,38 @@
+package converters
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/x509"
+	"errors"
+)
+
+func PrivateKeyToDER(key crypto.PrivateKey) (data []byte, err error) {
+	if key == nil {
+		err = errors.New(`nil key passed`)
+		return
+	}
+
+	return x509.MarshalPKCS8PrivateKey(key)
+}
+
+// CertificatesToDER concatenates DER encoded certificates, which is what x509.ParseCertificates expects
+func CertificatesToDER(certificates []*x509.Certificate) (data []byte, err error) {
+	if len(certificates) < 1 {
+		err = errors.New(`empty certificates slice passed`)
+		return
+	}
+
+	buffer := &bytes.Buffer{}
+	for _, certificate := range certificates {
+		if certificate == nil {
+			err = errors.New(`nil certificate passed`)
+			return
+		}
+		buffer.Write(certificate.Raw)
+	}
+
+	data = buffer.Bytes()
+
+	return
+}
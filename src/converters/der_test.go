@@ -0,0 +1,46 @@
+package converters
+
+import (
+	"testing"
+)
+
+func TestPrivateKeyToDER(t *testing.T) {
+	key, _ := newTestChain(t)
+
+	data, err := PrivateKeyToDER(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys, certificates, err := DecodeKeysAndCertificates(data, ``)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 1 || len(certificates) != 0 {
+		t.Fatal(`only the key expected`)
+	}
+	assertSameKey(t, keys[0], key)
+}
+
+func TestCertificatesToDER(t *testing.T) {
+	_, certificates := newTestChain(t)
+
+	data, err := CertificatesToDER(certificates)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys, decoded, err := DecodeKeysAndCertificates(data, ``)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 0 {
+		t.Fatal(`no key expected`)
+	}
+	assertSameCertificates(t, decoded, certificates)
+
+	_, err = CertificatesToDER(nil)
+	if err == nil {
+		t.Fatal(`empty chain was accepted`)
+	}
+}
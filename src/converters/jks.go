@@ -0,0 +1,52 @@
+package converters
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/x509"
+	"errors"
+	"github.com/pavlo-v-chernykh/keystore-go/v4"
+	"time"
+)
+
+func KeyAndCertificatesToJKS(key crypto.PrivateKey, certificates []*x509.Certificate, alias string, password string) (data []byte, err error) {
+	if key == nil {
+		err = errors.New(`nil key passed`)
+		return
+	}
+
+	if len(certificates) < 1 {
+		err = errors.New(`empty certificates slice passed`)
+		return
+	}
+
+	keyBytes, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return
+	}
+
+	entry := keystore.PrivateKeyEntry{
+		CreationTime:     time.Now(),
+		PrivateKey:       keyBytes,
+		CertificateChain: make([]keystore.Certificate, 0, len(certificates)),
+	}
+	for _, certificate := range certificates {
+		entry.CertificateChain = append(entry.CertificateChain, keystore.Certificate{Type: `X509`, Content: certificate.Raw})
+	}
+
+	keyStore := keystore.New()
+	err = keyStore.SetPrivateKeyEntry(alias, entry, []byte(password))
+	if err != nil {
+		return
+	}
+
+	buffer := &bytes.Buffer{}
+	err = keyStore.Store(buffer, []byte(password))
+	if err != nil {
+		return
+	}
+
+	data = buffer.Bytes()
+
+	return
+}
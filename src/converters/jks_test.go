@@ -0,0 +1,47 @@
+package converters
+
+import (
+	"bytes"
+	"crypto/x509"
+	"github.com/pavlo-v-chernykh/keystore-go/v4"
+	"testing"
+)
+
+func TestKeyAndCertificatesToJKS(t *testing.T) {
+	key, certificates := newTestChain(t)
+
+	data, err := KeyAndCertificatesToJKS(key, certificates, `server`, `changeit`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyStore := keystore.New()
+	err = keyStore.Load(bytes.NewReader(data), []byte(`changeit`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entry, err := keyStore.GetPrivateKeyEntry(`server`, []byte(`changeit`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decodedKey, err := x509.ParsePKCS8PrivateKey(entry.PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertSameKey(t, decodedKey, key)
+
+	decoded := make([]*x509.Certificate, 0, len(entry.CertificateChain))
+	for _, certificate := range entry.CertificateChain {
+		if certificate.Type != `X509` {
+			t.Fatal(`X509 certificate type expected`)
+		}
+		parsed, err := x509.ParseCertificate(certificate.Content)
+		if err != nil {
+			t.Fatal(err)
+		}
+		decoded = append(decoded, parsed)
+	}
+	assertSameCertificates(t, decoded, certificates)
+}
@@ -0,0 +1,23 @@
+package converters
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"errors"
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+func KeyAndCertificatesToPKCS12(key crypto.PrivateKey, certificates []*x509.Certificate, password string) (data []byte, err error) {
+	if key == nil {
+		err = errors.New(`nil key passed`)
+		return
+	}
+
+	if len(certificates) < 1 {
+		err = errors.New(`empty certificates slice passed`)
+		return
+	}
+
+	return pkcs12.Encode(rand.Reader, key, certificates[0], certificates[1:], password)
+}
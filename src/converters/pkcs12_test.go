@@ -0,0 +1,38 @@
+package converters
+
+import (
+	"crypto/x509"
+	"software.sslmate.com/src/go-pkcs12"
+	"testing"
+)
+
+func TestKeyAndCertificatesToPKCS12(t *testing.T) {
+	key, certificates := newTestChain(t)
+
+	data, err := KeyAndCertificatesToPKCS12(key, certificates, `secret`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decodedKey, certificate, caCertificates, err := pkcs12.DecodeChain(data, `secret`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertSameKey(t, decodedKey, key)
+	assertSameCertificates(t, append([]*x509.Certificate{certificate}, caCertificates...), certificates)
+
+	keys, decoded, err := DecodeKeysAndCertificates(data, `secret`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 1 {
+		t.Fatal(`one key expected`)
+	}
+	assertSameKey(t, keys[0], key)
+	assertSameCertificates(t, decoded, certificates)
+
+	_, _, err = DecodeKeysAndCertificates(data, `wrong`)
+	if err == nil {
+		t.Fatal(`wrong password was accepted`)
+	}
+}
@@ -0,0 +1,65 @@
+package converters
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/asn1"
+	"errors"
+)
+
+var (
+	oidPKCS7Data       = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 1}
+	oidPKCS7SignedData = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 2}
+)
+
+type pkcs7ContentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue `asn1:"optional"`
+}
+
+type pkcs7EmptyContentInfo struct {
+	ContentType asn1.ObjectIdentifier
+}
+
+type pkcs7SignedData struct {
+	Version          int
+	DigestAlgorithms asn1.RawValue
+	ContentInfo      pkcs7EmptyContentInfo
+	Certificates     asn1.RawValue
+	SignerInfos      asn1.RawValue
+}
+
+// CertificatesToPKCS7 builds degenerate "certs-only" PKCS#7 SignedData (the .p7b format) in DER encoding
+func CertificatesToPKCS7(certificates []*x509.Certificate) (data []byte, err error) {
+	if len(certificates) < 1 {
+		err = errors.New(`empty certificates slice passed`)
+		return
+	}
+
+	rawCertificates := &bytes.Buffer{}
+	for _, certificate := range certificates {
+		if certificate == nil {
+			err = errors.New(`nil certificate passed`)
+			return
+		}
+		rawCertificates.Write(certificate.Raw)
+	}
+
+	emptySet := asn1.RawValue{Class: asn1.ClassUniversal, Tag: asn1.TagSet, IsCompound: true, Bytes: []byte{}}
+
+	signedData, err := asn1.Marshal(pkcs7SignedData{
+		Version:          1,
+		DigestAlgorithms: emptySet,
+		ContentInfo:      pkcs7EmptyContentInfo{ContentType: oidPKCS7Data},
+		Certificates:     asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: rawCertificates.Bytes()},
+		SignerInfos:      emptySet,
+	})
+	if err != nil {
+		return
+	}
+
+	return asn1.Marshal(pkcs7ContentInfo{
+		ContentType: oidPKCS7SignedData,
+		Content:     asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: signedData},
+	})
+}
@@ -0,0 +1,55 @@
+package converters
+
+import (
+	"crypto/x509"
+	"encoding/asn1"
+	"testing"
+)
+
+func TestCertificatesToPKCS7(t *testing.T) {
+	_, certificates := newTestChain(t)
+
+	data, err := CertificatesToPKCS7(certificates)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	contentInfo := pkcs7ContentInfo{}
+	rest, err := asn1.Unmarshal(data, &contentInfo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rest) > 0 || !contentInfo.ContentType.Equal(oidPKCS7SignedData) {
+		t.Fatal(`SignedData content info expected`)
+	}
+	if contentInfo.Content.Class != asn1.ClassContextSpecific || contentInfo.Content.Tag != 0 {
+		t.Fatal(`content should be explicitly tagged [0]`)
+	}
+
+	signedData := pkcs7SignedData{}
+	rest, err = asn1.Unmarshal(contentInfo.Content.Bytes, &signedData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rest) > 0 || signedData.Version != 1 || !signedData.ContentInfo.ContentType.Equal(oidPKCS7Data) {
+		t.Fatal(`degenerate SignedData expected`)
+	}
+	if signedData.DigestAlgorithms.Tag != asn1.TagSet || len(signedData.DigestAlgorithms.Bytes) > 0 ||
+		signedData.SignerInfos.Tag != asn1.TagSet || len(signedData.SignerInfos.Bytes) > 0 {
+		t.Fatal(`certs-only SignedData should have no digest algorithms and signers`)
+	}
+	if signedData.Certificates.Class != asn1.ClassContextSpecific || signedData.Certificates.Tag != 0 {
+		t.Fatal(`certificates should be implicitly tagged [0]`)
+	}
+
+	decoded, err := x509.ParseCertificates(signedData.Certificates.Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertSameCertificates(t, decoded, certificates)
+
+	_, err = CertificatesToPKCS7(nil)
+	if err == nil {
+		t.Fatal(`empty chain was accepted`)
+	}
+}
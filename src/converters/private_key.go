@@ -53,15 +53,7 @@ func PrivateKeysToPEMBlocks[T keytype.Private](anyKeys []T) (pemBlocks []*pem.Bl
 }
 
 func PEMBlockToPrivateKey[T keytype.Private](pemBlock *pem.Block) (key T, err error) {
-	var anyKey crypto.PrivateKey
-	switch pemBlock.Type {
-	case privateKeyTypeRSA:
-		anyKey, err = x509.ParsePKCS1PrivateKey(pemBlock.Bytes)
-	case privateKeyTypeEC:
-		anyKey, err = x509.ParseECPrivateKey(pemBlock.Bytes)
-	default:
-		anyKey, err = x509.ParsePKCS8PrivateKey(pemBlock.Bytes)
-	}
+	anyKey, err := parsePrivateKey(pemBlock.Type, pemBlock.Bytes)
 	if err == nil {
 		var ok bool
 		key, ok = anyKey.(T)
@@ -94,3 +86,16 @@ func PEMBlocksToPrivateKeys[T keytype.Private](pemBlocks []*pem.Block) (keys []T
 
 	return
 }
+
+func parsePrivateKey(blockType string, keyBytes []byte) (anyKey crypto.PrivateKey, err error) {
+	switch blockType {
+	case privateKeyTypeRSA:
+		anyKey, err = x509.ParsePKCS1PrivateKey(keyBytes)
+	case privateKeyTypeEC:
+		anyKey, err = x509.ParseECPrivateKey(keyBytes)
+	default:
+		anyKey, err = x509.ParsePKCS8PrivateKey(keyBytes)
+	}
+
+	return
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"errors"
+	"math/rand"
+	"os"
+	"os/signal"
+	"ssl/config"
+	"ssl/legoadapter"
+	loglib "ssl/logger"
+	"ssl/ratelimit"
+	"syscall"
+	"time"
+)
+
+// reloadConfig reads configuration again with the same sources and overrides, set by wrapper
+var reloadConfig func() (config.ConfigInterface, error)
+
+func newDaemonCommand() *command {
+	return &command{
+		description: `run renewal check periodically until SIGTERM, SIGHUP re-reads config`,
+		run: func(appConfig config.ConfigInterface, args []string) error {
+			if len(args) > 0 {
+				return errors.New(`unexpected arguments passed`)
+			}
+			return runDaemon(appConfig)
+		},
+	}
+}
+
+// runOutcome tells how the last run ended, it decides the delay before the next one
+type runOutcome int
+
+const (
+	outcomeScheduled runOutcome = iota
+	// outcomeFailed is a run failed for our own reasons, e.g. config or hooks
+	outcomeFailed
+	// outcomeBackoff is a run failed on CA side
+	outcomeBackoff
+	outcomeRateLimited
+)
+
+type daemonSchedule struct {
+	Interval   time.Duration
+	Jitter     time.Duration
+	BackoffMin time.Duration
+	BackoffMax time.Duration
+}
+
+func getDaemonSchedule(appConfig config.ConfigInterface) daemonSchedule {
+	return daemonSchedule{
+		Interval:   appConfig.GetDaemonInterval(),
+		Jitter:     appConfig.GetDaemonJitter(),
+		BackoffMin: appConfig.GetDaemonBackoffMin(),
+		BackoffMax: appConfig.GetDaemonBackoffMax(),
+	}
+}
+
+func runDaemon(appConfig config.ConfigInterface) error {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM, syscall.SIGINT, syscall.SIGHUP)
+	defer signal.Stop(signals)
+
+	stopMetricsServer := startMetricsServer(appConfig)
+	defer func() {
+		stopMetricsServer()
+	}()
+
+	random := rand.New(rand.NewSource(time.Now().UnixNano()))
+	failures := 0
+
+	for {
+		err := app(appConfig, appOptions{})
+		writeMetricsTextfile(appConfig)
+
+		var delay time.Duration
+		var outcome runOutcome
+		delay, outcome, failures = getNextRunDelay(err, failures, getDaemonSchedule(appConfig), time.Now(), random)
+		switch outcome {
+		case outcomeRateLimited:
+			logger.With(loglib.Fields{`duration`: delay}).Warnf(`%s, next run in %s`, err, delay)
+		case outcomeBackoff:
+			logger.Error(err)
+			logger.With(loglib.Fields{`failures`: failures, `duration`: delay}).Warnf(`CA failed %d time(s) in a row, retrying in %s`, failures, delay)
+		case outcomeFailed:
+			logger.Error(err)
+			logger.With(loglib.Fields{`duration`: delay}).Warnf(`run failed, next run in %s`, delay)
+		default:
+			logger.With(loglib.Fields{`duration`: delay}).Infof(`next run in %s`, delay)
+		}
+
+		nextRun := time.Now().Add(delay)
+		for waiting := true; waiting; {
+			timer := time.NewTimer(time.Until(nextRun))
+			select {
+			case <-timer.C:
+				waiting = false
+			case sig := <-signals:
+				timer.Stop()
+				if sig != syscall.SIGHUP {
+					logger.Infof(`received "%s", shutting down`, sig)
+					return nil
+				}
+
+				newConfig, err := reloadConfig()
+				if err != nil {
+					logger.Errorf(`config reload failed, previous config is kept: %s`, err)
+					continue
+				}
+
+				// schedule is read from config on every run, only metrics server has to be restarted
+				if newConfig.GetMetricsListen() != appConfig.GetMetricsListen() {
+					stopMetricsServer()
+					stopMetricsServer = startMetricsServer(newConfig)
+					logger.Infof(`metrics server restarted at "%s"`, newConfig.GetMetricsListen())
+				}
+
+				appConfig = newConfig
+				logger.Infof(`config reloaded`)
+
+				// new config is checked at once, but CA backoff or rate limit wait is kept
+				waiting = outcome == outcomeBackoff || outcome == outcomeRateLimited
+				if waiting {
+					logger.Infof(`next run is kept in %s`, time.Until(nextRun).Round(time.Second))
+				}
+			}
+		}
+	}
+}
+
+// getNextRunDelay returns delay before the next run and updated count of CA failures in a row.
+// Only CA errors are retried with backoff, other errors wait for the usual interval as retrying would not help.
+// Waiting for CA rate limit is not a failure, backoff would only retry too early.
+func getNextRunDelay(runErr error, failures int, schedule daemonSchedule, now time.Time, random *rand.Rand) (delay time.Duration, outcome runOutcome, nextFailures int) {
+	var limitErr *ratelimit.Error
+	if errors.As(runErr, &limitErr) {
+		delay = limitErr.NextAllowed.Sub(now)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, outcomeRateLimited, failures
+	}
+
+	if legoadapter.IsCAError(runErr) {
+		nextFailures = failures + 1
+		return getBackoffDelay(nextFailures, schedule.BackoffMin, schedule.BackoffMax), outcomeBackoff, nextFailures
+	}
+
+	delay = getJitteredDelay(schedule.Interval, schedule.Jitter, random)
+	if runErr != nil && !errors.Is(runErr, NoChangeError) {
+		return delay, outcomeFailed, 0
+	}
+
+	return delay, outcomeScheduled, 0
+}
+
+// getBackoffDelay doubles delay starting from minDelay with every failure in a row
+func getBackoffDelay(failures int, minDelay time.Duration, maxDelay time.Duration) time.Duration {
+	delay := minDelay
+	for i := 1; i < failures; i++ {
+		delay *= 2
+		if delay >= maxDelay {
+			return maxDelay
+		}
+	}
+
+	if delay > maxDelay {
+		return maxDelay
+	}
+
+	return delay
+}
+
+func getJitteredDelay(interval time.Duration, jitter time.Duration, random *rand.Rand) time.Duration {
+	if jitter <= 0 {
+		return interval
+	}
+
+	return interval + time.Duration(random.Int63n(int64(jitter)))
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"errors"
+	"math/rand"
+	"ssl/ratelimit"
+	"testing"
+	"time"
+)
+
+var testSchedule = daemonSchedule{
+	Interval:   12 * time.Hour,
+	Jitter:     time.Hour,
+	BackoffMin: time.Minute,
+	BackoffMax: 6 * time.Minute,
+}
+
+func TestGetNextRunDelay_Scheduled(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+
+	for _, runErr := range []error{nil, NoChangeError} {
+		delay, outcome, failures := getNextRunDelay(runErr, 3, testSchedule, time.Now(), random)
+		if outcome != outcomeScheduled || failures != 0 {
+			t.Fatal(`successful run should reset failures`)
+		}
+		if delay < testSchedule.Interval || delay >= testSchedule.Interval+testSchedule.Jitter {
+			t.Fatalf(`delay %s is out of jittered interval`, delay)
+		}
+	}
+
+	delay, _, _ := getNextRunDelay(nil, 0, daemonSchedule{Interval: time.Hour}, time.Now(), random)
+	if delay != time.Hour {
+		t.Fatal(`interval without jitter should be used as is`)
+	}
+}
+
+func TestGetNextRunDelay_Backoff(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	runErr := errors.New(`503 :: POST :: https://ca.example/new-order :: unexpected response`)
+
+	expected := []time.Duration{time.Minute, 2 * time.Minute, 4 * time.Minute, 6 * time.Minute, 6 * time.Minute}
+	failures := 0
+	for i, expectedDelay := range expected {
+		var delay time.Duration
+		var outcome runOutcome
+		delay, outcome, failures = getNextRunDelay(runErr, failures, testSchedule, time.Now(), random)
+		if outcome != outcomeBackoff || failures != i+1 {
+			t.Fatal(`CA failure should be counted`)
+		}
+		if delay != expectedDelay {
+			t.Fatalf(`failure %d: delay %s expected, got %s`, failures, expectedDelay, delay)
+		}
+	}
+}
+
+func TestGetNextRunDelay_OwnError(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+
+	delay, outcome, failures := getNextRunDelay(errors.New(`postRenew hook failed`), 3, testSchedule, time.Now(), random)
+	if outcome != outcomeFailed {
+		t.Fatal(`run should be reported as failed`)
+	}
+	if failures != 0 {
+		t.Fatal(`own error should end CA failures in a row`)
+	}
+	if delay < testSchedule.Interval || delay >= testSchedule.Interval+testSchedule.Jitter {
+		t.Fatalf(`own error should not be retried with backoff, delay is %s`, delay)
+	}
+}
+
+func TestGetNextRunDelay_RateLimited(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	now := time.Now()
+
+	delay, outcome, failures := getNextRunDelay(&ratelimit.Error{NextAllowed: now.Add(3 * time.Hour)}, 2, testSchedule, now, random)
+	if outcome != outcomeRateLimited || delay != 3*time.Hour {
+		t.Fatalf(`run should wait until the limit allows an order, delay is %s`, delay)
+	}
+	if failures != 2 {
+		t.Fatal(`waiting for rate limit should not change failures`)
+	}
+
+	delay, _, _ = getNextRunDelay(&ratelimit.Error{NextAllowed: now.Add(-time.Minute)}, 0, testSchedule, now, random)
+	if delay != 0 {
+		t.Fatal(`passed limit should not give negative delay`)
+	}
+}
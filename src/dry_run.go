@@ -0,0 +1,84 @@
+package main
+
+import (
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"ssl/config"
+	"ssl/hooks"
+	"ssl/ratelimit"
+	"ssl/storage/file"
+	"strings"
+)
+
+func dryRunApp(appConfig config.ConfigInterface, options appOptions) (err error) {
+	plan := file.NewPlan()
+	file.SetPlan(plan)
+	defer file.SetPlan(nil)
+
+	options.dryRun = true
+	err = app(appConfig, options)
+
+	printPlan(os.Stdout, plan.Operations())
+	printPlannedOrder(os.Stdout, appConfig, err)
+
+	return
+}
+
+func printPlannedOrder(w io.Writer, appConfig config.ConfigInterface, err error) {
+	var limitErr *ratelimit.Error
+
+	switch {
+	case err == nil && appConfig.GetVaultEnabled():
+		_, _ = fmt.Fprintf(w, "certificate: would be requested from vault for %s\n", strings.Join(appConfig.GetDomains(), `, `))
+	case err == nil && appConfig.GetLocalCAEnabled():
+		_, _ = fmt.Fprintf(w, "certificate: would be issued by local CA for %s\n", strings.Join(appConfig.GetDomains(), `, `))
+	case err == nil:
+		_, _ = fmt.Fprintf(w, "acme order: would be placed at %s CA for %s\n", strings.Join(getACMEProviderNames(appConfig), ` or `), strings.Join(appConfig.GetDomains(), `, `))
+	case errors.Is(err, NoChangeError):
+		_, _ = fmt.Fprintln(w, `acme order: not needed`)
+	case errors.As(err, &limitErr):
+		_, _ = fmt.Fprintf(w, "acme order: would be refused, %s\n", limitErr)
+	default:
+		_, _ = fmt.Fprintln(w, `acme order: unknown, dry run failed`)
+	}
+}
+
+// planRenewal logs steps renewal would take after preRenew hooks, nothing reaches CA or endpoints
+func planRenewal(appConfig config.ConfigInterface, bundleManager *MultiBundleManager[*rsa.PrivateKey], variables map[string]string) error {
+	logger.Infof(`dry run: certificate would be ordered for %s`, strings.Join(appConfig.GetDomains(), `, `))
+
+	err := runHooks(hooks.EventDeploy, appConfig.GetDeployHooks(), variables, true)
+	if err != nil {
+		return err
+	}
+
+	err = runHooks(hooks.EventPostRenew, appConfig.GetPostRenewHooks(), variables, true)
+	if err != nil {
+		return err
+	}
+
+	return verifyEndpoints(appConfig, bundleManager, variables, true)
+}
+
+func getACMEProviderNames(appConfig config.ConfigInterface) []string {
+	names := make([]string, 0)
+	for _, provider := range appConfig.GetACMEProviders() {
+		names = append(names, `"`+provider.Name+`"`)
+	}
+	return names
+}
+
+func printPlan(w io.Writer, operations []file.PlannedOperation) {
+	if len(operations) < 1 {
+		_, _ = fmt.Fprintln(w, `files: no changes`)
+		return
+	}
+
+	_, _ = fmt.Fprintln(w, `files:`)
+	for _, operation := range operations {
+		_, _ = fmt.Fprintf(w, "  %-9s %s\n", operation.Operation, operation.Filename)
+	}
+}
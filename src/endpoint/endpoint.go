@@ -0,0 +1,128 @@
+package endpoint
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+type Endpoint struct {
+	// Address is "host:port" to connect to
+	Address string
+	// ServerName is sent as SNI and checked against the served leaf
+	ServerName string
+}
+
+func (e Endpoint) String() string {
+	if e.ServerName == `` {
+		return e.Address
+	}
+	return e.Address + ` (` + e.ServerName + `)`
+}
+
+// Error lists differences between served and expected certificates of one endpoint
+type Error struct {
+	Endpoint Endpoint
+	Problems []string
+}
+
+func (e *Error) Error() string {
+	return `endpoint ` + e.Endpoint.String() + `: ` + strings.Join(e.Problems, `; `)
+}
+
+// Fetch performs TLS handshake and returns certificates served by the endpoint.
+// Trust is not checked here, served certificates are compared with expected ones instead.
+func Fetch(endpoint Endpoint, timeout time.Duration) (certificates []*x509.Certificate, err error) {
+	dialer := &net.Dialer{Timeout: timeout}
+	connection, err := tls.DialWithDialer(dialer, `tcp`, endpoint.Address, &tls.Config{
+		ServerName:         endpoint.ServerName,
+		InsecureSkipVerify: true,
+	})
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = connection.Close()
+	}()
+
+	return connection.ConnectionState().PeerCertificates, nil
+}
+
+// Verify returns *Error if endpoint does not serve expected leaf with its intermediates
+func Verify(endpoint Endpoint, expected []*x509.Certificate, timeout time.Duration) error {
+	served, err := Fetch(endpoint, timeout)
+	if err != nil {
+		return &Error{Endpoint: endpoint, Problems: []string{`handshake failed: ` + err.Error()}}
+	}
+
+	problems := GetChainProblems(served, expected, endpoint.ServerName)
+	if len(problems) > 0 {
+		return &Error{Endpoint: endpoint, Problems: problems}
+	}
+
+	return nil
+}
+
+// GetChainProblems compares served chain with expected one: leaf goes first and matches,
+// every certificate is signed by the next one and no expected intermediate is missing.
+func GetChainProblems(served []*x509.Certificate, expected []*x509.Certificate, serverName string) (problems []string) {
+	if len(expected) < 1 {
+		return []string{`no expected certificate`}
+	}
+	if len(served) < 1 {
+		return []string{`no certificate served`}
+	}
+
+	leaf := expected[0]
+	if !served[0].Equal(leaf) {
+		problems = append(problems, fmt.Sprintf(`served certificate serial %s does not match expected serial %s`,
+			served[0].SerialNumber.Text(16), leaf.SerialNumber.Text(16)))
+	}
+
+	if serverName != `` {
+		err := served[0].VerifyHostname(serverName)
+		if err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+
+	for i := 0; i < len(served)-1; i++ {
+		err := served[i].CheckSignatureFrom(served[i+1])
+		if err != nil {
+			problems = append(problems, fmt.Sprintf(`served certificate %d is not signed by the next one`, i))
+		}
+	}
+
+	for _, intermediate := range expected[1:] {
+		if !containsCertificate(served[1:], intermediate) {
+			problems = append(problems, `intermediate "`+intermediate.Subject.CommonName+`" is not served`)
+		}
+	}
+
+	return
+}
+
+func containsCertificate(certificates []*x509.Certificate, certificate *x509.Certificate) bool {
+	for _, item := range certificates {
+		if item.Equal(certificate) {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateAddress is used by config to reject endpoints without port
+func ValidateAddress(address string) error {
+	_, port, err := net.SplitHostPort(address)
+	if err == nil && port == `` {
+		err = errors.New(`port is empty`)
+	}
+	if err != nil {
+		return errors.New(`endpoint address "` + address + `" is not host:port: ` + err.Error())
+	}
+	return nil
+}
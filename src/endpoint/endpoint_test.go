@@ -0,0 +1,153 @@
+package endpoint
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testBundle struct {
+	key   *ecdsa.PrivateKey
+	chain []*x509.Certificate
+}
+
+var intermediateKey *ecdsa.PrivateKey
+var intermediateCertificate *x509.Certificate
+
+func init() {
+	rootKey := generateKey()
+	rootCertificate := generateCertificate(1, `root`, nil, &rootKey.PublicKey, nil, rootKey)
+	intermediateKey = generateKey()
+	intermediateCertificate = generateCertificate(2, `intermediate`, nil, &intermediateKey.PublicKey, rootCertificate, rootKey)
+}
+
+func newTestBundle(serial int64) testBundle {
+	key := generateKey()
+	leaf := generateCertificate(serial, `example.com`, []string{`example.com`}, &key.PublicKey, intermediateCertificate, intermediateKey)
+	return testBundle{key: key, chain: []*x509.Certificate{leaf, intermediateCertificate}}
+}
+
+// startServer serves the bundle with the passed certificates, e.g. a chain without intermediate
+func startServer(t *testing.T, bundle testBundle, served []*x509.Certificate) string {
+	certificate := tls.Certificate{PrivateKey: bundle.key}
+	for _, item := range served {
+		certificate.Certificate = append(certificate.Certificate, item.Raw)
+	}
+
+	listener, err := tls.Listen(`tcp`, `127.0.0.1:0`, &tls.Config{Certificates: []tls.Certificate{certificate}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = listener.Close()
+	})
+
+	go func() {
+		for {
+			connection, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			_ = connection.(*tls.Conn).Handshake()
+			_ = connection.Close()
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+func TestVerify(t *testing.T) {
+	bundle := newTestBundle(10)
+	address := startServer(t, bundle, bundle.chain)
+
+	err := Verify(Endpoint{Address: address, ServerName: `example.com`}, bundle.chain, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestVerifyMismatch(t *testing.T) {
+	oldBundle := newTestBundle(10)
+	newBundle := newTestBundle(11)
+	address := startServer(t, oldBundle, oldBundle.chain[:1])
+
+	err := Verify(Endpoint{Address: address, ServerName: `www.example.org`}, newBundle.chain, time.Second)
+	var endpointErr *Error
+	if !errors.As(err, &endpointErr) {
+		t.Fatal(`endpoint error expected, got: `, err)
+	}
+
+	message := err.Error()
+	for _, expected := range []string{`does not match expected serial b`, `www.example.org`, `"intermediate" is not served`} {
+		if !strings.Contains(message, expected) {
+			t.Fatal(`"` + expected + `" expected in: ` + message)
+		}
+	}
+}
+
+func TestVerifyWrongOrder(t *testing.T) {
+	bundle := newTestBundle(10)
+	problems := GetChainProblems([]*x509.Certificate{bundle.chain[0], newTestBundle(12).chain[0], bundle.chain[1]}, bundle.chain, ``)
+	if len(problems) != 1 || !strings.Contains(problems[0], `certificate 0 is not signed`) {
+		t.Fatal(`broken served chain should be reported: `, problems)
+	}
+}
+
+func TestVerifyUnreachable(t *testing.T) {
+	listener, err := net.Listen(`tcp`, `127.0.0.1:0`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := listener.Addr().String()
+	_ = listener.Close()
+
+	err = Verify(Endpoint{Address: address}, newTestBundle(10).chain, time.Second)
+	if err == nil || !strings.Contains(err.Error(), `handshake failed`) {
+		t.Fatal(`handshake error expected, got: `, err)
+	}
+}
+
+func generateKey() *ecdsa.PrivateKey {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	return key
+}
+
+func generateCertificate(serial int64, commonName string, dnsNames []string, publicKey *ecdsa.PublicKey, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) *x509.Certificate {
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(serial),
+		Subject:               pkix.Name{CommonName: commonName},
+		DNSNames:              dnsNames,
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		BasicConstraintsValid: true,
+		IsCA:                  parent == nil || dnsNames == nil,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+	}
+	if parent == nil {
+		parent = template
+	}
+
+	raw, err := x509.CreateCertificate(rand.Reader, template, parent, publicKey, parentKey)
+	if err != nil {
+		panic(err)
+	}
+
+	certificate, err := x509.ParseCertificate(raw)
+	if err != nil {
+		panic(err)
+	}
+
+	return certificate
+}
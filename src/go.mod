@@ -2,13 +2,19 @@ module ssl
 
 go 1.18
 
-require github.com/go-acme/lego/v4 v4.6.0
+require (
+	github.com/BurntSushi/toml v1.3.2
+	github.com/go-acme/lego/v4 v4.6.0
+	github.com/miekg/dns v1.1.43
+	github.com/pavlo-v-chernykh/keystore-go/v4 v4.5.0
+	golang.org/x/crypto v0.0.0-20220331220935-ae2d96664a29
+	golang.org/x/net v0.0.0-20211112202133-69e39bad7dc2
+	gopkg.in/yaml.v3 v3.0.1
+	software.sslmate.com/src/go-pkcs12 v0.2.0
+)
 
 require (
 	github.com/cenkalti/backoff/v4 v4.1.1 // indirect
-	github.com/miekg/dns v1.1.43 // indirect
-	golang.org/x/crypto v0.0.0-20210616213533-5ff15b29337e // indirect
-	golang.org/x/net v0.0.0-20210510120150-4163338589ed // indirect
 	golang.org/x/sys v0.0.0-20210615035016-665e8c7367d1 // indirect
 	golang.org/x/text v0.3.6 // indirect
 	gopkg.in/square/go-jose.v2 v2.6.0 // indirect
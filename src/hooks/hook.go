@@ -0,0 +1,115 @@
+package hooks
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+)
+
+type Event string
+
+const (
+	EventPreRenew  Event = `pre_renew`
+	EventPostRenew Event = `post_renew`
+	EventDeploy    Event = `deploy`
+	EventFailure   Event = `failure`
+)
+
+const shell = `/bin/sh`
+
+type Hook struct {
+	Command string
+	Timeout time.Duration
+}
+
+// Error is returned when hook command fails or times out
+type Error struct {
+	Event   Event
+	Command string
+	Err     error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf(`%s hook "%s" failed: %s`, e.Event, e.Command, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Run executes hooks one by one with shell, passing variables in environment.
+// All hooks are run even if some of them fail, first error is returned.
+func Run(event Event, hooks []Hook, variables map[string]string) (err error) {
+	environment := os.Environ()
+	environment = append(environment, `SSL_HOOK_EVENT=`+string(event))
+
+	names := make([]string, 0, len(variables))
+	for name := range variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		environment = append(environment, name+`=`+variables[name])
+	}
+
+	for _, hook := range hooks {
+		hookErr := run(hook, environment)
+		if hookErr != nil {
+			hookErr = &Error{Event: event, Command: hook.Command, Err: hookErr}
+			logger.Errorf(`%s`, hookErr)
+			if err == nil {
+				err = hookErr
+			}
+			continue
+		}
+		logger.Infof(`%s hook "%s" succeeded`, event, hook.Command)
+	}
+
+	return
+}
+
+func run(hook Hook, environment []string) (err error) {
+	output := &bytes.Buffer{}
+	cmd := exec.Command(shell, `-c`, hook.Command)
+	cmd.Env = environment
+	cmd.Stdout = output
+	cmd.Stderr = output
+	setProcessGroup(cmd)
+
+	err = cmd.Start()
+	if err != nil {
+		return
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+
+	var timeout <-chan time.Time
+	if hook.Timeout > 0 {
+		timer := time.NewTimer(hook.Timeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case err = <-done:
+	case <-timeout:
+		// whole process group is killed, so children of the shell do not outlive the hook
+		killProcessGroup(cmd)
+		<-done
+		err = errors.New(`timed out after ` + hook.Timeout.String())
+	}
+
+	if output.Len() > 0 {
+		logger.Infof("hook \"%s\" output:\n%s", hook.Command, strings.TrimRight(output.String(), "\n"))
+	}
+
+	return
+}
@@ -0,0 +1,93 @@
+package hooks
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRun_Environment(t *testing.T) {
+	output := filepath.Join(t.TempDir(), `output.txt`)
+
+	err := Run(
+		EventPostRenew,
+		[]Hook{{Command: `echo "$SSL_HOOK_EVENT $SSL_NEW_SERIAL" > ` + output, Timeout: time.Second}},
+		map[string]string{`SSL_NEW_SERIAL`: `abc`},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(data) != "post_renew abc\n" {
+		t.Fatalf(`hook got wrong environment "%s"`, data)
+	}
+}
+
+// levelLogger keeps levels of logged messages
+type levelLogger struct {
+	levels []string
+}
+
+func (l *levelLogger) Infof(format string, args ...interface{}) {
+	l.levels = append(l.levels, `info`)
+}
+
+func (l *levelLogger) Warnf(format string, args ...interface{}) {
+	l.levels = append(l.levels, `warn`)
+}
+
+func (l *levelLogger) Errorf(format string, args ...interface{}) {
+	l.levels = append(l.levels, `error`)
+}
+
+func TestRun_Failure(t *testing.T) {
+	output := filepath.Join(t.TempDir(), `output.txt`)
+
+	levels := &levelLogger{}
+	SetLogger(levels)
+	defer SetLogger(&defaultLogger{})
+
+	err := Run(
+		EventPreRenew,
+		[]Hook{{Command: `exit 3`}, {Command: `touch ` + output}},
+		nil,
+	)
+
+	var hookErr *Error
+	if !errors.As(err, &hookErr) {
+		t.Fatal(`hook failure was not reported`)
+	}
+
+	if hookErr.Event != EventPreRenew {
+		t.Fatal(`wrong event reported`)
+	}
+
+	_, err = os.Stat(output)
+	if err != nil {
+		t.Fatal(`hooks after failed one were not run`)
+	}
+
+	if len(levels.levels) != 2 || levels.levels[0] != `error` || levels.levels[1] != `info` {
+		t.Fatal(`failed hook should be logged as error, success as info`)
+	}
+}
+
+func TestRun_Timeout(t *testing.T) {
+	started := time.Now()
+
+	err := Run(EventFailure, []Hook{{Command: `sleep 5`, Timeout: 100 * time.Millisecond}}, nil)
+	if err == nil {
+		t.Fatal(`timeout was not reported`)
+	}
+
+	if time.Since(started) > 3*time.Second {
+		t.Fatal(`hook was not stopped on timeout`)
+	}
+}
@@ -0,0 +1,35 @@
+package hooks
+
+import (
+	"fmt"
+)
+
+type loggerInterface interface {
+	Infof(format string, args ...interface{})
+	Warnf(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+}
+
+type defaultLogger struct{}
+
+func (l *defaultLogger) Infof(format string, args ...interface{}) {
+	fmt.Printf(format, args...)
+}
+
+func (l *defaultLogger) Warnf(format string, args ...interface{}) {
+	fmt.Printf(format, args...)
+}
+
+func (l *defaultLogger) Errorf(format string, args ...interface{}) {
+	fmt.Printf(format, args...)
+}
+
+var logger loggerInterface
+
+func init() {
+	SetLogger(&defaultLogger{})
+}
+
+func SetLogger(loggr loggerInterface) {
+	logger = loggr
+}
@@ -0,0 +1,13 @@
+//go:build windows
+
+package hooks
+
+import (
+	"os/exec"
+)
+
+func setProcessGroup(cmd *exec.Cmd) {}
+
+func killProcessGroup(cmd *exec.Cmd) {
+	_ = cmd.Process.Kill()
+}
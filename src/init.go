@@ -2,34 +2,65 @@ package main
 
 import (
 	"errors"
+	"os"
 	"ssl/certs"
 	"ssl/config"
+	"ssl/hooks"
+	"ssl/issuer"
 	loglib "ssl/logger"
 )
 
 var logger LoggerInterface
 
 type LoggerInterface interface {
+	Debugf(format string, args ...interface{})
 	Infof(format string, args ...interface{})
+	Warnf(format string, args ...interface{})
 	Errorf(format string, args ...interface{})
 	Error(err error)
+	With(fields loglib.Fields) loglib.Logger
 }
 
 func init() {
 	logger = loglib.Make(`main`)
 	config.SetLogger(loglib.Make(`config`))
 	certs.SetLogger(loglib.Make(`certs`))
+	hooks.SetLogger(loglib.Make(`hooks`))
+	issuer.SetLogger(loglib.Make(`issuer`))
 }
 
-func getConfig(envVarKeyEnvironment, envVarKeyConfigFolder string) (conf config.ConfigInterface, err error) {
-	conf, errs := config.Initialize(envVarKeyEnvironment, envVarKeyConfigFolder)
+func getConfig(envVarKeyEnvironment, envVarKeyConfigFolder string, overrides config.Overrides) (conf config.ConfigInterface, err error) {
+	conf, errs := config.Initialize(envVarKeyEnvironment, envVarKeyConfigFolder, overrides)
 
 	if len(errs) > 0 {
 		err = errors.New(`config is not valid`)
 		for _, subErr := range errs {
 			logger.Error(subErr)
 		}
+		return
 	}
 
+	err = configureLogger(conf)
+
 	return
 }
+
+// configureLogger applies log settings from config, environment variables take precedence over level and format
+func configureLogger(appConfig config.ConfigInterface) (err error) {
+	level, exists := os.LookupEnv(loglib.LevelEnvKey)
+	if !exists {
+		level = appConfig.GetLogLevel()
+	}
+
+	format, exists := os.LookupEnv(loglib.FormatEnvKey)
+	if !exists {
+		format = appConfig.GetLogFormat()
+	}
+
+	err = loglib.Configure(level, format)
+	if err != nil {
+		return
+	}
+
+	return loglib.ConfigureSink(appConfig.GetLogSinkSettings())
+}
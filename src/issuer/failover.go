@@ -0,0 +1,178 @@
+package issuer
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Candidate is one CA of the failover list, issuer is created only when the CA is tried
+type Candidate struct {
+	Name string
+	New  func() (Issuer, error)
+	// Check returns why the CA should not be tried now, e.g. local rate limit, nil if it can be
+	Check func() error
+}
+
+// Attempt is the result of an order placed at one candidate
+type Attempt struct {
+	Name string
+	Err  error
+}
+
+type FailoverSettings struct {
+	Candidates []Candidate
+	// ShouldFailover tells if error is on CA side, e.g. outage or rate limit
+	ShouldFailover func(err error) bool
+	// IssuedBy returns name of the candidate which issued the certificate, empty if unknown
+	IssuedBy func(certificate *x509.Certificate) string
+}
+
+// Failover tries candidates in order. Next candidate is tried only if ShouldFailover
+// says the error is on CA side, our own mistakes are returned as is.
+type Failover struct {
+	settings FailoverSettings
+	attempts []Attempt
+	// last is the candidate which handled the last attempt
+	last string
+}
+
+func NewFailover(settings FailoverSettings) (*Failover, error) {
+	if len(settings.Candidates) < 1 {
+		return nil, errors.New(`no CA configured`)
+	}
+	if settings.ShouldFailover == nil {
+		return nil, errors.New(`failover condition is not set`)
+	}
+
+	return &Failover{
+		settings: settings,
+		last:     settings.Candidates[0].Name,
+	}, nil
+}
+
+// Name returns CA which issued the last certificate or failed the last attempt
+func (f *Failover) Name() string {
+	return f.last
+}
+
+// Attempts returns orders placed by the last Obtain in order, skipped candidates are not included
+func (f *Failover) Attempts() []Attempt {
+	attempts := make([]Attempt, len(f.attempts))
+	copy(attempts, f.attempts)
+	return attempts
+}
+
+// Obtain skips candidates which fail their check, unless all of them do.
+// Callers are expected to refuse such orders beforehand unless they are forced.
+func (f *Failover) Obtain(request Request) (certificates []*x509.Certificate, err error) {
+	f.attempts = nil
+
+	candidates := f.getAvailableCandidates()
+	failures := make([]string, 0, len(candidates))
+	for i, candidate := range candidates {
+		f.last = candidate.Name
+
+		certificates, err = f.obtain(candidate, request)
+		f.attempts = append(f.attempts, Attempt{Name: candidate.Name, Err: err})
+		if err == nil {
+			logger.Infof(`certificate issued by "%s" CA, tried: %s`, candidate.Name, f.getPath())
+			return
+		}
+
+		if !f.settings.ShouldFailover(err) {
+			break
+		}
+		if i < len(candidates)-1 {
+			failures = append(failures, candidate.Name+`: `+err.Error())
+			logger.Warnf(`"%s" CA failed on its side, falling back to "%s": %s`, candidate.Name, candidates[i+1].Name, err)
+		}
+	}
+
+	if len(failures) < 1 {
+		return
+	}
+
+	// last error is wrapped, so callers can still tell preflight or rate limit errors
+	prefix := `all CAs failed`
+	if !f.settings.ShouldFailover(err) {
+		prefix = `CA fallback stopped on own error`
+	}
+	err = fmt.Errorf(`%s: %s; %s: %w`, prefix, strings.Join(failures, `; `), f.last, err)
+
+	return
+}
+
+func (f *Failover) getAvailableCandidates() []Candidate {
+	available := make([]Candidate, 0, len(f.settings.Candidates))
+	for _, candidate := range f.settings.Candidates {
+		if candidate.Check != nil {
+			err := candidate.Check()
+			if err != nil {
+				logger.Warnf(`"%s" CA is skipped: %s`, candidate.Name, err)
+				continue
+			}
+		}
+		available = append(available, candidate)
+	}
+
+	if len(available) < 1 {
+		logger.Warnf(`no CA passed its check, all of them are tried`)
+		return f.settings.Candidates
+	}
+
+	return available
+}
+
+func (f *Failover) getPath() string {
+	names := make([]string, 0, len(f.attempts))
+	for _, attempt := range f.attempts {
+		names = append(names, attempt.Name)
+	}
+	return strings.Join(names, ` -> `)
+}
+
+func (f *Failover) obtain(candidate Candidate, request Request) ([]*x509.Certificate, error) {
+	certificateIssuer, err := candidate.New()
+	if err != nil {
+		return nil, err
+	}
+	return certificateIssuer.Obtain(request)
+}
+
+// Revoke asks only the CA which issued the certificate, so no accounts are created at other CAs.
+// The only candidate is assumed to be the issuer if it is unknown.
+func (f *Failover) Revoke(certificate *x509.Certificate, reason int) (err error) {
+	name := ``
+	if f.settings.IssuedBy != nil {
+		name = f.settings.IssuedBy(certificate)
+	}
+	if name == `` && len(f.settings.Candidates) == 1 {
+		name = f.settings.Candidates[0].Name
+	}
+	if name == `` {
+		return errors.New(`CA which issued certificate with serial "` + certificate.SerialNumber.Text(16) + `" is unknown`)
+	}
+
+	for _, candidate := range f.settings.Candidates {
+		if candidate.Name != name {
+			continue
+		}
+
+		f.last = candidate.Name
+
+		var certificateIssuer Issuer
+		certificateIssuer, err = candidate.New()
+		if err != nil {
+			return
+		}
+		return certificateIssuer.Revoke(certificate, reason)
+	}
+
+	return errors.New(`CA "` + name + `" which issued certificate is not configured`)
+}
+
+func (f *Failover) RenewalInfo(certificate *x509.Certificate, issuerCertificate *x509.Certificate) (*RenewalInfo, error) {
+	return nil, ErrNotSupported
+}
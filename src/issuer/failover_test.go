@@ -0,0 +1,168 @@
+package issuer
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+var errOutage = errors.New(`503 service unavailable`)
+var errUnauthorized = errors.New(`unauthorized`)
+
+type stubIssuer struct {
+	name  string
+	err   error
+	calls *[]string
+}
+
+func (s *stubIssuer) Name() string {
+	return s.name
+}
+
+func (s *stubIssuer) Obtain(request Request) ([]*x509.Certificate, error) {
+	*s.calls = append(*s.calls, s.name)
+	if s.err != nil {
+		return nil, s.err
+	}
+	return []*x509.Certificate{{}}, nil
+}
+
+func (s *stubIssuer) Revoke(certificate *x509.Certificate, reason int) error {
+	*s.calls = append(*s.calls, s.name)
+	return s.err
+}
+
+func (s *stubIssuer) RenewalInfo(certificate *x509.Certificate, issuerCertificate *x509.Certificate) (*RenewalInfo, error) {
+	return nil, ErrNotSupported
+}
+
+func newTestFailover(t *testing.T, calls *[]string, errs map[string]error, checks map[string]error) *Failover {
+	candidates := make([]Candidate, 0)
+	for _, name := range []string{`primary`, `secondary`, `tertiary`} {
+		stub := &stubIssuer{name: name, err: errs[name], calls: calls}
+		checkErr := checks[name]
+		candidates = append(candidates, Candidate{
+			Name: name,
+			New: func() (Issuer, error) {
+				return stub, nil
+			},
+			Check: func() error {
+				return checkErr
+			},
+		})
+	}
+
+	failover, err := NewFailover(FailoverSettings{
+		Candidates: candidates,
+		ShouldFailover: func(err error) bool {
+			return errors.Is(err, errOutage)
+		},
+		IssuedBy: func(certificate *x509.Certificate) string {
+			return certificate.Subject.CommonName
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return failover
+}
+
+func getAttemptNames(attempts []Attempt) string {
+	names := make([]string, 0, len(attempts))
+	for _, attempt := range attempts {
+		names = append(names, attempt.Name)
+	}
+	return strings.Join(names, `,`)
+}
+
+func testRequest() Request {
+	return Request{Identifiers: []string{`example.com`}, CSR: &x509.CertificateRequest{}}
+}
+
+func TestFailoverOnCAError(t *testing.T) {
+	calls := make([]string, 0)
+	failover := newTestFailover(t, &calls, map[string]error{`primary`: errOutage}, nil)
+
+	_, err := failover.Obtain(testRequest())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(calls, `,`) != `primary,secondary` || failover.Name() != `secondary` {
+		t.Fatal(`secondary CA should issue after primary outage, calls: ` + strings.Join(calls, `,`))
+	}
+
+	attempts := failover.Attempts()
+	if getAttemptNames(attempts) != `primary,secondary` || !errors.Is(attempts[0].Err, errOutage) || attempts[1].Err != nil {
+		t.Fatal(`every attempt should be reported`)
+	}
+}
+
+func TestFailoverStopsOnOwnError(t *testing.T) {
+	calls := make([]string, 0)
+	failover := newTestFailover(t, &calls, map[string]error{`primary`: errOutage, `secondary`: errUnauthorized}, nil)
+
+	_, err := failover.Obtain(testRequest())
+	if !errors.Is(err, errUnauthorized) {
+		t.Fatal(`own mistake should be returned as is, got: `, err)
+	}
+	if strings.Join(calls, `,`) != `primary,secondary` || failover.Name() != `secondary` {
+		t.Fatal(`failover should stop on own mistake, calls: ` + strings.Join(calls, `,`))
+	}
+}
+
+func TestFailoverAllFailed(t *testing.T) {
+	calls := make([]string, 0)
+	failover := newTestFailover(t, &calls, map[string]error{`primary`: errOutage, `secondary`: errOutage, `tertiary`: errOutage}, nil)
+
+	_, err := failover.Obtain(testRequest())
+	if err == nil || !strings.Contains(err.Error(), `all CAs failed: primary: `) || !strings.Contains(err.Error(), `tertiary: `) {
+		t.Fatal(`every CA failure should be reported, got: `, err)
+	}
+	if !errors.Is(err, errOutage) {
+		t.Fatal(`last CA error should be wrapped`)
+	}
+}
+
+func TestFailoverSkipsCheckedCandidates(t *testing.T) {
+	calls := make([]string, 0)
+	failover := newTestFailover(t, &calls, nil, map[string]error{`primary`: errOutage})
+
+	_, err := failover.Obtain(testRequest())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(calls, `,`) != `secondary` || getAttemptNames(failover.Attempts()) != `secondary` {
+		t.Fatal(`candidate failing its check should be skipped, calls: ` + strings.Join(calls, `,`))
+	}
+}
+
+func TestFailoverTriesAllIfAllChecksFail(t *testing.T) {
+	calls := make([]string, 0)
+	failover := newTestFailover(t, &calls, nil, map[string]error{`primary`: errOutage, `secondary`: errOutage, `tertiary`: errOutage})
+
+	_, err := failover.Obtain(testRequest())
+	if err != nil || strings.Join(calls, `,`) != `primary` {
+		t.Fatal(`first candidate should be tried if every check fails, calls: ` + strings.Join(calls, `,`))
+	}
+}
+
+func TestFailoverRevokesAtIssuer(t *testing.T) {
+	calls := make([]string, 0)
+	failover := newTestFailover(t, &calls, nil, nil)
+
+	certificate := &x509.Certificate{Subject: pkix.Name{CommonName: `secondary`}, SerialNumber: big.NewInt(1)}
+	err := failover.Revoke(certificate, 0)
+	if err != nil || strings.Join(calls, `,`) != `secondary` {
+		t.Fatal(`only the issuing CA should be asked to revoke, calls: ` + strings.Join(calls, `,`))
+	}
+
+	certificate.Subject.CommonName = ``
+	err = failover.Revoke(certificate, 0)
+	if err == nil || len(calls) != 1 {
+		t.Fatal(`revocation at unknown CA should be refused`)
+	}
+}
@@ -0,0 +1,47 @@
+package issuer
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"errors"
+	"time"
+)
+
+// ErrNotSupported is returned by issuers which do not implement an optional operation
+var ErrNotSupported = errors.New(`operation is not supported by issuer`)
+
+// Request describes certificate to obtain, either Key or CSR has to be set
+type Request struct {
+	Identifiers []string
+	Key         *rsa.PrivateKey
+	CSR         *x509.CertificateRequest
+}
+
+func (r Request) Validate() error {
+	if len(r.Identifiers) < 1 {
+		return errors.New(`no identifiers requested`)
+	}
+	if (r.Key == nil) == (r.CSR == nil) {
+		return errors.New(`either private key or CSR has to be passed`)
+	}
+	return nil
+}
+
+// RenewalInfo is a renewal window suggested by issuer, e.g. by ACME ARI
+type RenewalInfo struct {
+	WindowStart    time.Time
+	WindowEnd      time.Time
+	ExplanationURL string
+}
+
+// Issuer obtains and revokes certificates. ACME CA, local CA or a fake in tests implement it.
+type Issuer interface {
+	// Name is used in logs and metrics, e.g. "acme" or "local-ca"
+	Name() string
+	// Obtain returns leaf certificate followed by intermediates
+	Obtain(request Request) ([]*x509.Certificate, error)
+	// Revoke uses RFC 5280 reason codes
+	Revoke(certificate *x509.Certificate, reason int) error
+	// RenewalInfo returns ErrNotSupported if issuer does not suggest renewal windows
+	RenewalInfo(certificate *x509.Certificate, issuerCertificate *x509.Certificate) (*RenewalInfo, error)
+}
@@ -0,0 +1,77 @@
+package issuer
+
+import (
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"ssl/storage"
+	"time"
+)
+
+type registryEntry struct {
+	Issuer   string    `json:"issuer"`
+	NotAfter time.Time `json:"notAfter"`
+}
+
+// Registry remembers which issuer handled every certificate, so it can be revoked at the right CA later
+type Registry struct {
+	// Certificates are keyed by hex serial number
+	Certificates map[string]*registryEntry `json:"certificates"`
+}
+
+// LoadRegistry reads registry from store, empty store gives empty registry
+func LoadRegistry(store storage.Byte) (registry *Registry, err error) {
+	registry = &Registry{Certificates: make(map[string]*registryEntry)}
+
+	data, err := store.Load()
+	if err != nil {
+		if errors.Is(err, storage.EmptyNode) {
+			err = nil
+		}
+		return
+	}
+	if len(data) < 1 {
+		return
+	}
+
+	err = json.Unmarshal(data, registry)
+	if err != nil {
+		return nil, errors.New(`issuer registry is corrupted: ` + err.Error())
+	}
+	if registry.Certificates == nil {
+		registry.Certificates = make(map[string]*registryEntry)
+	}
+
+	return
+}
+
+func (r *Registry) Save(store storage.Byte) error {
+	data, err := json.MarshalIndent(r, ``, `  `)
+	if err != nil {
+		return err
+	}
+	return store.Save(data)
+}
+
+// Record adds the certificate and drops expired ones, so the registry does not grow forever
+func (r *Registry) Record(certificate *x509.Certificate, issuerName string, now time.Time) {
+	for serial, entry := range r.Certificates {
+		if now.After(entry.NotAfter) {
+			delete(r.Certificates, serial)
+		}
+	}
+
+	r.Certificates[certificate.SerialNumber.Text(16)] = &registryEntry{
+		Issuer:   issuerName,
+		NotAfter: certificate.NotAfter.UTC(),
+	}
+}
+
+// GetIssuer returns empty string if the certificate is unknown
+func (r *Registry) GetIssuer(certificate *x509.Certificate) string {
+	entry, ok := r.Certificates[certificate.SerialNumber.Text(16)]
+	if !ok {
+		return ``
+	}
+	return entry.Issuer
+}
@@ -0,0 +1,41 @@
+package issuer
+
+import (
+	"crypto/x509"
+	"math/big"
+	"ssl/storage/memory"
+	"testing"
+	"time"
+)
+
+func TestRegistry(t *testing.T) {
+	now := time.Now()
+	store := memory.NewByteMemory()
+
+	registry, err := LoadRegistry(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expired := &x509.Certificate{SerialNumber: big.NewInt(1), NotAfter: now.Add(time.Hour)}
+	current := &x509.Certificate{SerialNumber: big.NewInt(2), NotAfter: now.Add(90 * 24 * time.Hour)}
+	registry.Record(expired, `primary`, now)
+	registry.Record(current, `secondary`, now.Add(2*time.Hour))
+
+	err = registry.Save(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	registry, err = LoadRegistry(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if registry.GetIssuer(current) != `secondary` {
+		t.Fatal(`issuer of certificate was not remembered`)
+	}
+	if registry.GetIssuer(expired) != `` {
+		t.Fatal(`expired certificate should be dropped`)
+	}
+}
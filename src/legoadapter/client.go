@@ -7,10 +7,13 @@ import (
 	"github.com/go-acme/lego/v4/registration"
 )
 
-func GetLegoClient(user registration.User, useStagingCA bool) (*lego.Client, error) {
+// GetLegoClient uses Let's Encrypt directory if caDirURL is empty
+func GetLegoClient(user registration.User, useStagingCA bool, caDirURL string) (*lego.Client, error) {
 	LEconfig := lego.NewConfig(user)
 
-	if useStagingCA {
+	if caDirURL != `` {
+		LEconfig.CADirURL = caDirURL
+	} else if useStagingCA {
 		LEconfig.CADirURL = lego.LEDirectoryStaging
 	} else {
 		LEconfig.CADirURL = lego.LEDirectoryProduction
@@ -22,13 +25,22 @@ func GetLegoClient(user registration.User, useStagingCA bool) (*lego.Client, err
 	return lego.NewClient(LEconfig)
 }
 
-func LoginOrRegisterIfNotExists(client *lego.Client) (resource *registration.Resource, err error) {
+// LoginOrRegisterIfNotExists binds new account to external account if eab is not nil
+func LoginOrRegisterIfNotExists(client *lego.Client, eab *ExternalAccountBinding) (resource *registration.Resource, err error) {
 	resource, err = client.Registration.ResolveAccountByKey()
 	if err != nil {
 		er, ok := err.(*acme.ProblemDetails)
 		if ok && er.Type == `urn:ietf:params:acme:error:accountDoesNotExist` {
 			// New users will need to register
-			resource, err = client.Registration.Register(registration.RegisterOptions{TermsOfServiceAgreed: true})
+			if eab != nil {
+				resource, err = client.Registration.RegisterWithExternalAccountBinding(registration.RegisterEABOptions{
+					TermsOfServiceAgreed: true,
+					Kid:                  eab.KeyID,
+					HmacEncoded:          eab.HMACKey,
+				})
+			} else {
+				resource, err = client.Registration.Register(registration.RegisterOptions{TermsOfServiceAgreed: true})
+			}
 		}
 	}
 
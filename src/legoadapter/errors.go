@@ -0,0 +1,124 @@
+package legoadapter
+
+import (
+	"errors"
+	"github.com/go-acme/lego/v4/acme"
+	"net"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const acmeErrorNamespace = `urn:ietf:params:acme:error:`
+
+// caSideProblems are ACME problem types caused by CA state, not by our request
+var caSideProblems = map[string]bool{
+	acmeErrorNamespace + `serverInternal`: true,
+	acmeErrorNamespace + `rateLimited`:    true,
+	acmeErrorNamespace + `badNonce`:       true,
+}
+
+// validationProblems are ACME problem types of failed challenges, CA counts them in its failed validation limit
+var validationProblems = map[string]bool{
+	acmeErrorNamespace + `unauthorized`:      true,
+	acmeErrorNamespace + `caa`:               true,
+	acmeErrorNamespace + `connection`:        true,
+	acmeErrorNamespace + `dns`:               true,
+	acmeErrorNamespace + `incorrectResponse`: true,
+	acmeErrorNamespace + `tls`:               true,
+}
+
+// statusPrefixRegexp matches errors of non-JSON CA responses, e.g. "502 :: POST :: https://...",
+// also wrapped ones like "get directory at '<url>': 503 :: GET :: ..."
+var statusPrefixRegexp = regexp.MustCompile(`(?:^|: )(\d{3}) ?::`)
+
+// IsCAError tells if an order failed for CA side reasons: 5xx, rate limit, timeout or unreachable CA.
+// Our own mistakes, e.g. unauthorized, CAA or DNS problems, are not CA errors.
+func IsCAError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	domainErrors := getDomainErrors(err)
+	if len(domainErrors) > 0 {
+		for _, domainErr := range domainErrors {
+			if !IsCAError(domainErr) {
+				return false
+			}
+		}
+		return true
+	}
+
+	var nonceErr *acme.NonceError
+	if errors.As(err, &nonceErr) {
+		return true
+	}
+
+	var problem *acme.ProblemDetails
+	if errors.As(err, &problem) {
+		return caSideProblems[problem.Type] || problem.HTTPStatus >= http.StatusInternalServerError || problem.HTTPStatus == http.StatusTooManyRequests
+	}
+
+	// failed bind of challenge server is our host, only reaching CA counts
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op != `listen` && (opErr.Op == `dial` || opErr.Timeout()) {
+		return true
+	}
+	if opErr == nil {
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			return true
+		}
+	}
+
+	if matches := statusPrefixRegexp.FindStringSubmatch(err.Error()); matches != nil {
+		status, _ := strconv.Atoi(matches[1])
+		return status >= http.StatusInternalServerError || status == http.StatusTooManyRequests
+	}
+
+	return strings.Contains(err.Error(), `time limit exceeded`)
+}
+
+// IsValidationError tells if CA failed to validate control of at least one identifier of an order
+func IsValidationError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	domainErrors := getDomainErrors(err)
+	if len(domainErrors) > 0 {
+		for _, domainErr := range domainErrors {
+			if IsValidationError(domainErr) {
+				return true
+			}
+		}
+		return false
+	}
+
+	var problem *acme.ProblemDetails
+	return errors.As(err, &problem) && validationProblems[problem.Type]
+}
+
+// getDomainErrors unpacks per domain errors of lego, its error type is an unexported map[string]error.
+// The map is also looked for in wrapped errors, e.g. of CA failover.
+func getDomainErrors(err error) (domainErrors []error) {
+	value := reflect.ValueOf(err)
+	errorType := reflect.TypeOf((*error)(nil)).Elem()
+	if value.Kind() != reflect.Map || value.Type().Key().Kind() != reflect.String || value.Type().Elem() != errorType {
+		if wrapped := errors.Unwrap(err); wrapped != nil {
+			return getDomainErrors(wrapped)
+		}
+		return nil
+	}
+
+	iterator := value.MapRange()
+	for iterator.Next() {
+		if domainErr, ok := iterator.Value().Interface().(error); ok && domainErr != nil {
+			domainErrors = append(domainErrors, domainErr)
+		}
+	}
+
+	return
+}
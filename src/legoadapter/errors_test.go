@@ -0,0 +1,86 @@
+package legoadapter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/go-acme/lego/v4/acme"
+	"net"
+	"net/url"
+	"ssl/preflight"
+	"syscall"
+	"testing"
+)
+
+// domainErrors has the shape of unexported per domain error of lego
+type domainErrors map[string]error
+
+func (d domainErrors) Error() string {
+	return `domain errors`
+}
+
+func TestIsCAError(t *testing.T) {
+	serverInternal := &acme.ProblemDetails{Type: acmeErrorNamespace + `serverInternal`, HTTPStatus: 500}
+	unauthorized := &acme.ProblemDetails{Type: acmeErrorNamespace + `unauthorized`, HTTPStatus: 403}
+
+	cases := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{`nil`, nil, false},
+		{`server internal`, serverInternal, true},
+		{`rate limited`, &acme.ProblemDetails{Type: acmeErrorNamespace + `rateLimited`, HTTPStatus: 429}, true},
+		{`bad gateway`, &acme.ProblemDetails{HTTPStatus: 502}, true},
+		{`bad nonce`, &acme.NonceError{ProblemDetails: &acme.ProblemDetails{Type: acmeErrorNamespace + `badNonce`}}, true},
+		{`unauthorized`, unauthorized, false},
+		{`caa`, &acme.ProblemDetails{Type: acmeErrorNamespace + `caa`, HTTPStatus: 403}, false},
+		{`timeout`, &net.OpError{Op: `dial`, Err: context.DeadlineExceeded}, true},
+		{`non json response`, errors.New(`503 :: POST :: https://ca.example/new-order :: unexpected response`), true},
+		{`non json client error`, errors.New(`404 :: GET :: https://ca.example/directory :: not found`), false},
+		{`wrapped bind`, fmt.Errorf(`could not start HTTP server for challenge: %w`, &net.OpError{Op: `listen`, Net: `tcp`, Err: syscall.EADDRINUSE}), false},
+		{`wrapped dial timeout`, fmt.Errorf(`get directory at 'https://ca.example/directory': %w`, &url.Error{Op: `Get`, URL: `https://ca.example/directory`, Err: &net.OpError{Op: `dial`, Net: `tcp`, Err: context.DeadlineExceeded}}), true},
+		{`wrapped directory outage`, errors.New(`get directory at 'https://ca.example/directory': 503 :: GET :: https://ca.example/directory :: unexpected response`), true},
+		{`polling`, errors.New(`certificate polling: time limit exceeded`), true},
+		{`preflight`, &preflight.Error{}, false},
+		{`wrapped`, errors.New(`order: ` + unauthorized.Error()), false},
+		{`all domains on CA side`, domainErrors{`a.example`: serverInternal, `b.example`: errors.New(`time limit exceeded`)}, true},
+		{`one domain unauthorized`, domainErrors{`a.example`: serverInternal, `b.example`: unauthorized}, false},
+		{`wrapped domain errors`, fmt.Errorf(`all CAs failed: primary: timeout; backup: %w`, domainErrors{`a.example`: serverInternal}), true},
+		{`wrapped domain error unauthorized`, fmt.Errorf(`all CAs failed: primary: timeout; backup: %w`, domainErrors{`a.example`: unauthorized}), false},
+	}
+
+	for _, c := range cases {
+		if IsCAError(c.err) != c.want {
+			t.Fatal(`IsCAError of "` + c.name + `" is wrong`)
+		}
+	}
+}
+
+func TestIsValidationError(t *testing.T) {
+	serverInternal := &acme.ProblemDetails{Type: acmeErrorNamespace + `serverInternal`, HTTPStatus: 500}
+	unauthorized := &acme.ProblemDetails{Type: acmeErrorNamespace + `unauthorized`, HTTPStatus: 403}
+
+	cases := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{`nil`, nil, false},
+		{`unauthorized`, unauthorized, true},
+		{`dns`, &acme.ProblemDetails{Type: acmeErrorNamespace + `dns`, HTTPStatus: 400}, true},
+		{`server internal`, serverInternal, false},
+		{`rate limited`, &acme.ProblemDetails{Type: acmeErrorNamespace + `rateLimited`, HTTPStatus: 429}, false},
+		{`timeout`, &net.OpError{Op: `dial`, Err: context.DeadlineExceeded}, false},
+		{`preflight`, &preflight.Error{}, false},
+		{`config`, errors.New(`account key is not readable`), false},
+		{`one domain unauthorized`, domainErrors{`a.example`: serverInternal, `b.example`: unauthorized}, true},
+		{`wrapped domain errors`, fmt.Errorf(`all CAs failed: primary: timeout; backup: %w`, domainErrors{`a.example`: fmt.Errorf(`challenge: %w`, unauthorized)}), true},
+	}
+
+	for _, c := range cases {
+		if IsValidationError(c.err) != c.want {
+			t.Fatal(`IsValidationError of "` + c.name + `" is wrong`)
+		}
+	}
+}
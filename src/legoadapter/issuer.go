@@ -0,0 +1,121 @@
+package legoadapter
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"github.com/go-acme/lego/v4/certificate"
+	"github.com/go-acme/lego/v4/challenge/http01"
+	"github.com/go-acme/lego/v4/lego"
+	"ssl/converters"
+	"ssl/issuer"
+	"strconv"
+)
+
+// ExternalAccountBinding links ACME account to an account at CA, required by some CAs
+type ExternalAccountBinding struct {
+	KeyID string
+	// HMACKey is base64url encoded
+	HMACKey string
+}
+
+type IssuerSettings struct {
+	AccountKey   *rsa.PrivateKey
+	Email        string
+	UseStagingCA bool
+	// DirectoryURL overrides Let's Encrypt directory chosen by UseStagingCA
+	DirectoryURL string
+	EAB          *ExternalAccountBinding
+	// HTTPPort is where HTTP-01 challenge server listens
+	HTTPPort int
+	// Preflight is run before every order if set, account URI is passed for CAA checks
+	Preflight func(accountURI string, identifiers []string) error
+}
+
+// Issuer obtains certificates from ACME CA with HTTP-01 challenge
+type Issuer struct {
+	client     *lego.Client
+	accountURI string
+	preflight  func(accountURI string, identifiers []string) error
+}
+
+// NewIssuer connects to CA and registers account if it does not exist yet
+func NewIssuer(settings IssuerSettings) (acmeIssuer *Issuer, err error) {
+	user := GenerateLegoUser(settings.AccountKey, settings.Email)
+
+	client, err := GetLegoClient(user, settings.UseStagingCA, settings.DirectoryURL)
+	if err != nil {
+		return
+	}
+
+	resource, err := LoginOrRegisterIfNotExists(client, settings.EAB)
+	if err != nil {
+		return
+	}
+	user.Registration = resource
+
+	err = client.Challenge.SetHTTP01Provider(http01.NewProviderServer(``, strconv.Itoa(settings.HTTPPort)))
+	if err != nil {
+		return
+	}
+
+	return &Issuer{client: client, accountURI: resource.URI, preflight: settings.Preflight}, nil
+}
+
+func (i *Issuer) Name() string {
+	return `acme`
+}
+
+// AccountURI is used for CAA accounturi binding checks
+func (i *Issuer) AccountURI() string {
+	return i.accountURI
+}
+
+func (i *Issuer) Obtain(request issuer.Request) (certificates []*x509.Certificate, err error) {
+	err = request.Validate()
+	if err != nil {
+		return
+	}
+
+	if i.preflight != nil {
+		err = i.preflight(i.accountURI, request.Identifiers)
+		if err != nil {
+			return
+		}
+	}
+
+	var certificateBytes []byte
+	if request.CSR != nil {
+		var resource *certificate.Resource
+		resource, err = i.client.Certificate.ObtainForCSR(certificate.ObtainForCSRRequest{CSR: request.CSR, Bundle: true})
+		if err != nil {
+			return
+		}
+		certificateBytes = resource.Certificate
+	} else {
+		certificateBytes, err = RequestCertificateBytesForDomains(i.client, request.Identifiers, request.Key)
+		if err != nil {
+			return
+		}
+	}
+
+	_, certificates, err = converters.DecodeKeysAndCertificates(certificateBytes, ``)
+	if err == nil && len(certificates) < 1 {
+		err = errors.New(`CA returned no certificates`)
+	}
+
+	return
+}
+
+func (i *Issuer) Revoke(revoked *x509.Certificate, reason int) error {
+	certificateBytes := pem.EncodeToMemory(&pem.Block{Type: `CERTIFICATE`, Bytes: revoked.Raw})
+	reasonCode := uint(reason)
+
+	return i.client.Certificate.RevokeWithReason(certificateBytes, &reasonCode)
+}
+
+// RenewalInfo is not supported, ACME client does not implement ARI
+func (i *Issuer) RenewalInfo(certificate *x509.Certificate, issuerCertificate *x509.Certificate) (*issuer.RenewalInfo, error) {
+	return nil, issuer.ErrNotSupported
+}
@@ -0,0 +1,116 @@
+package localca
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"net"
+	"time"
+)
+
+// backdate covers clock skew between the issuing machine and clients
+const backdate = time.Hour
+
+// CA signs certificates offline, it is meant for development environments only
+type CA struct {
+	Key         *rsa.PrivateKey
+	Certificate *x509.Certificate
+}
+
+// New generates a self-signed root with the passed key
+func New(key *rsa.PrivateKey, commonName string, validity time.Duration, now time.Time) (ca *CA, err error) {
+	serial, err := generateSerial()
+	if err != nil {
+		return
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             now.Add(-backdate),
+		NotAfter:              now.Add(validity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLenZero:        true,
+	}
+
+	raw, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		return
+	}
+
+	certificate, err := x509.ParseCertificate(raw)
+	if err != nil {
+		return
+	}
+
+	return &CA{Key: key, Certificate: certificate}, nil
+}
+
+// Load checks that the key belongs to the CA certificate
+func Load(key *rsa.PrivateKey, certificate *x509.Certificate) (ca *CA, err error) {
+	if key == nil || certificate == nil {
+		return nil, errors.New(`CA key and certificate are required`)
+	}
+
+	publicKey, ok := certificate.PublicKey.(*rsa.PublicKey)
+	if !ok || !key.PublicKey.Equal(publicKey) {
+		return nil, errors.New(`CA key does not match CA certificate`)
+	}
+
+	if !certificate.IsCA {
+		return nil, errors.New(`CA certificate is not a CA`)
+	}
+
+	return &CA{Key: key, Certificate: certificate}, nil
+}
+
+// Issue signs a server certificate for domains, IP addresses are put into IP SANs
+func (ca *CA) Issue(publicKey crypto.PublicKey, domains []string, validity time.Duration, now time.Time) (certificate *x509.Certificate, err error) {
+	if len(domains) < 1 {
+		return nil, errors.New(`no domains passed`)
+	}
+
+	notAfter := now.Add(validity)
+	if notAfter.After(ca.Certificate.NotAfter) {
+		return nil, errors.New(`CA certificate expires before the issued one, remove CA files to generate a new CA`)
+	}
+
+	serial, err := generateSerial()
+	if err != nil {
+		return
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: domains[0]},
+		NotBefore:             now.Add(-backdate),
+		NotAfter:              notAfter,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+	}
+	for _, domain := range domains {
+		if ip := net.ParseIP(domain); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, domain)
+		}
+	}
+
+	raw, err := x509.CreateCertificate(rand.Reader, template, ca.Certificate, publicKey, ca.Key)
+	if err != nil {
+		return
+	}
+
+	return x509.ParseCertificate(raw)
+}
+
+func generateSerial() (*big.Int, error) {
+	return rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+}
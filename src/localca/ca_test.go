@@ -0,0 +1,103 @@
+package localca
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"ssl/issuer"
+	"testing"
+	"time"
+)
+
+func generateKey(t *testing.T) *rsa.PrivateKey {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func TestIssue(t *testing.T) {
+	now := time.Now()
+	ca, err := New(generateKey(t), `dev CA`, 365*24*time.Hour, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	leafKey := generateKey(t)
+	certificate, err := ca.Issue(leafKey.Public(), []string{`example.test`, `127.0.0.1`}, 90*24*time.Hour, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.Certificate)
+	for _, name := range []string{`example.test`, `127.0.0.1`} {
+		_, err = certificate.Verify(x509.VerifyOptions{DNSName: name, Roots: roots})
+		if err != nil {
+			t.Fatal(`issued certificate should be trusted for ` + name + `: ` + err.Error())
+		}
+	}
+
+	_, err = ca.Issue(leafKey.Public(), []string{`example.test`}, 2*365*24*time.Hour, now)
+	if err == nil {
+		t.Fatal(`certificate outliving the CA should not be issued`)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	key := generateKey(t)
+	ca, err := New(key, `dev CA`, time.Hour, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = Load(key, ca.Certificate)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = Load(generateKey(t), ca.Certificate)
+	if err == nil {
+		t.Fatal(`mismatching key should not be loaded`)
+	}
+}
+
+func TestIssuerObtain(t *testing.T) {
+	ca, err := New(generateKey(t), `dev CA`, 365*24*time.Hour, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	certificateIssuer := NewIssuer(ca, 24*time.Hour)
+
+	key := generateKey(t)
+	csrBytes, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{DNSNames: []string{`example.test`}}, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	csr, err := x509.ParseCertificateRequest(csrBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, request := range []issuer.Request{
+		{Identifiers: []string{`example.test`}, Key: key},
+		{Identifiers: []string{`example.test`}, CSR: csr},
+	} {
+		certificates, err := certificateIssuer.Obtain(request)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(certificates) != 2 || !certificates[1].Equal(ca.Certificate) {
+			t.Fatal(`leaf followed by CA certificate expected`)
+		}
+		if !key.PublicKey.Equal(certificates[0].PublicKey) {
+			t.Fatal(`certificate is issued for another key`)
+		}
+	}
+
+	_, err = certificateIssuer.Obtain(issuer.Request{Identifiers: []string{`example.test`}})
+	if err == nil {
+		t.Fatal(`request without key and CSR should fail`)
+	}
+}
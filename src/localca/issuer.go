@@ -0,0 +1,61 @@
+package localca
+
+import (
+	"crypto"
+	"crypto/x509"
+	"ssl/issuer"
+	"time"
+)
+
+// Issuer signs certificates with local CA, CA certificate is returned as the issuer in the chain
+type Issuer struct {
+	ca       *CA
+	validity time.Duration
+	now      func() time.Time
+}
+
+func NewIssuer(ca *CA, validity time.Duration) *Issuer {
+	return &Issuer{
+		ca:       ca,
+		validity: validity,
+		now:      time.Now,
+	}
+}
+
+func (i *Issuer) Name() string {
+	return `local-ca`
+}
+
+func (i *Issuer) Obtain(request issuer.Request) (certificates []*x509.Certificate, err error) {
+	err = request.Validate()
+	if err != nil {
+		return
+	}
+
+	var publicKey crypto.PublicKey
+	if request.CSR != nil {
+		err = request.CSR.CheckSignature()
+		if err != nil {
+			return
+		}
+		publicKey = request.CSR.PublicKey
+	} else {
+		publicKey = request.Key.Public()
+	}
+
+	certificate, err := i.ca.Issue(publicKey, request.Identifiers, i.validity, i.now())
+	if err != nil {
+		return
+	}
+
+	return []*x509.Certificate{certificate, i.ca.Certificate}, nil
+}
+
+// Revoke is not supported, local CA publishes neither OCSP nor CRL
+func (i *Issuer) Revoke(certificate *x509.Certificate, reason int) error {
+	return issuer.ErrNotSupported
+}
+
+func (i *Issuer) RenewalInfo(certificate *x509.Certificate, issuerCertificate *x509.Certificate) (*issuer.RenewalInfo, error) {
+	return nil, issuer.ErrNotSupported
+}
@@ -0,0 +1,118 @@
+package logger
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+type format uint8
+
+const (
+	textFormat format = iota
+	jsonFormat
+)
+
+const textTimeLayout = `2006/01/02 15:04:05`
+
+func parseFormat(name string) (format, error) {
+	switch strings.ToLower(name) {
+	case `text`:
+		return textFormat, nil
+	case `json`:
+		return jsonFormat, nil
+	}
+
+	return textFormat, errors.New(`unknown log format "` + name + `"`)
+}
+
+func (f format) encode(r *record) []byte {
+	if f == jsonFormat {
+		return encodeJSON(r)
+	}
+	return encodeText(r)
+}
+
+// encodeText keeps "[module] [SEVERITY] message" layout, fields are appended as key=value
+func encodeText(r *record) []byte {
+	builder := &strings.Builder{}
+	builder.WriteString(r.time.Format(textTimeLayout))
+	builder.WriteString(` `)
+	if r.module != `` {
+		builder.WriteString(`[` + r.module + `] `)
+	}
+	if r.severity != undefined {
+		builder.WriteString(`[` + r.severity.String() + `] `)
+	}
+	builder.WriteString(r.message)
+
+	for _, key := range getSortedKeys(r.fields) {
+		builder.WriteString(` ` + key + `=`)
+		builder.WriteString(formatTextValue(r.fields[key]))
+	}
+
+	if !strings.HasSuffix(builder.String(), "\n") {
+		builder.WriteString("\n")
+	}
+
+	return []byte(builder.String())
+}
+
+func formatTextValue(value interface{}) string {
+	text := fmt.Sprint(normalizeValue(value))
+	if text == `` || strings.ContainsAny(text, " \t\n\"=") {
+		return fmt.Sprintf(`%q`, text)
+	}
+	return text
+}
+
+// encodeJSON writes one line per record, reserved keys win over fields with the same names
+func encodeJSON(r *record) []byte {
+	values := make(map[string]interface{}, len(r.fields)+4)
+	for key, value := range r.fields {
+		values[key] = normalizeValue(value)
+	}
+	values[`time`] = r.time.Format(time.RFC3339Nano)
+	values[`level`] = strings.ToLower(r.severity.String())
+	values[`module`] = r.module
+	values[`message`] = strings.TrimRight(r.message, "\n")
+
+	data, err := json.Marshal(values)
+	if err != nil {
+		data, _ = json.Marshal(map[string]interface{}{
+			`time`:    values[`time`],
+			`level`:   values[`level`],
+			`module`:  r.module,
+			`message`: values[`message`],
+			`error`:   `fields are not serializable: ` + err.Error(),
+		})
+	}
+
+	return append(data, '\n')
+}
+
+// normalizeValue makes values readable in both formats: durations as strings, errors as messages
+func normalizeValue(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case time.Duration:
+		return typed.String()
+	case error:
+		return typed.Error()
+	case fmt.Stringer:
+		return typed.String()
+	default:
+		return value
+	}
+}
+
+func getSortedKeys(fields Fields) []string {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -1,15 +1,119 @@
 package logger
 
 import (
-	"log"
+	"io"
 	"os"
+	"sort"
+	"time"
+)
+
+const (
+	LevelEnvKey  = `APP_LOG_LEVEL`
+	FormatEnvKey = `APP_LOG_FORMAT`
 )
 
 func init() {
-	loggers := make(map[severity]*log.Logger)
-	loggers[errorSeverity] = log.New(os.Stderr, ``, log.LstdFlags)
-	loggers[infoSeverity] = log.New(os.Stdout, ``, log.LstdFlags)
+	console := newConsoleSink()
 	mainLogger = &commonLogger{
-		loggers: loggers,
+		minLevel: infoSeverity,
+		format:   textFormat,
+		console:  console,
+		sink:     console,
+		now:      time.Now,
+	}
+
+	// environment is applied right away, so messages logged before config is read respect it too
+	_ = Configure(os.Getenv(LevelEnvKey), os.Getenv(FormatEnvKey))
+}
+
+// SetInfoOutput redirects informational messages, e.g. to keep stdout clean for command results
+func SetInfoOutput(w io.Writer) {
+	mainLogger.mutex.Lock()
+	defer mainLogger.mutex.Unlock()
+
+	mainLogger.console.outputs[debugSeverity] = w
+	mainLogger.console.outputs[infoSeverity] = w
+}
+
+// ConfigureSink replaces the sink records are written to, previous one is closed
+func ConfigureSink(settings SinkSettings) error {
+	selected, err := newSink(settings, mainLogger.console)
+	if err != nil {
+		return err
+	}
+
+	mainLogger.mutex.Lock()
+	defer mainLogger.mutex.Unlock()
+
+	if mainLogger.sink != selected {
+		_ = mainLogger.sink.close()
+	}
+	mainLogger.sink = selected
+
+	return nil
+}
+
+// Configure sets minimal level (debug, info, warn, error) and format (text, json), empty values are not changed
+func Configure(level string, formatName string) (err error) {
+	minLevel := mainLogger.minLevel
+	if level != `` {
+		minLevel, err = parseSeverity(level)
+		if err != nil {
+			return
+		}
+	}
+
+	outputFormat := mainLogger.format
+	if formatName != `` {
+		outputFormat, err = parseFormat(formatName)
+		if err != nil {
+			return
+		}
 	}
+
+	mainLogger.mutex.Lock()
+	defer mainLogger.mutex.Unlock()
+
+	mainLogger.minLevel = minLevel
+	mainLogger.format = outputFormat
+
+	return
+}
+
+// ValidateLevel and ValidateFormat let config check values without applying them
+func ValidateLevel(level string) error {
+	_, err := parseSeverity(level)
+	return err
+}
+
+func ValidateFormat(formatName string) error {
+	_, err := parseFormat(formatName)
+	return err
+}
+
+// RegisterSecrets makes logger mask these values in every record, e.g. passwords and tokens from config
+func RegisterSecrets(secrets ...string) {
+	mainLogger.mutex.Lock()
+	defer mainLogger.mutex.Unlock()
+
+	for _, secret := range secrets {
+		if len(secret) < minSecretLength {
+			continue
+		}
+		known := false
+		for _, existing := range mainLogger.secrets {
+			if existing == secret {
+				known = true
+				break
+			}
+		}
+		if !known {
+			mainLogger.secrets = append(mainLogger.secrets, secret)
+		}
+	}
+
+	// longer secrets go first, so secrets containing other ones are masked whole
+	sort.Slice(mainLogger.secrets, func(i, j int) bool {
+		return len(mainLogger.secrets[i]) > len(mainLogger.secrets[j])
+	})
 }
@@ -0,0 +1,89 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+const defaultJournaldSocket = `/run/systemd/journal/socket`
+
+// journaldSink speaks journald native protocol: one datagram per record with KEY=value lines
+type journaldSink struct {
+	conn *net.UnixConn
+	tag  string
+}
+
+func newJournaldSink(socket string, tag string) (*journaldSink, error) {
+	if socket == `` {
+		socket = defaultJournaldSocket
+	}
+
+	conn, err := net.DialUnix(`unixgram`, nil, &net.UnixAddr{Name: socket, Net: `unixgram`})
+	if err != nil {
+		return nil, err
+	}
+
+	return &journaldSink{conn: conn, tag: tag}, nil
+}
+
+func (s *journaldSink) write(r *record, _ format) error {
+	buffer := &bytes.Buffer{}
+	writeJournaldField(buffer, `MESSAGE`, strings.TrimRight(r.message, "\n"))
+	writeJournaldField(buffer, `PRIORITY`, strconv.Itoa(r.severity.priority()))
+	writeJournaldField(buffer, `SYSLOG_IDENTIFIER`, s.tag)
+	if r.module != `` {
+		writeJournaldField(buffer, `MODULE`, r.module)
+	}
+
+	for _, key := range getSortedKeys(r.fields) {
+		name := getJournaldFieldName(key)
+		if name == `` {
+			continue
+		}
+		writeJournaldField(buffer, name, fmt.Sprint(normalizeValue(r.fields[key])))
+	}
+
+	_, err := s.conn.Write(buffer.Bytes())
+	return err
+}
+
+func (s *journaldSink) close() error {
+	return s.conn.Close()
+}
+
+// writeJournaldField uses binary form for values with new lines, as protocol requires
+func writeJournaldField(buffer *bytes.Buffer, name string, value string) {
+	if !strings.Contains(value, "\n") {
+		buffer.WriteString(name + `=` + value + "\n")
+		return
+	}
+
+	buffer.WriteString(name + "\n")
+	_ = binary.Write(buffer, binary.LittleEndian, uint64(len(value)))
+	buffer.WriteString(value + "\n")
+}
+
+// getJournaldFieldName converts field keys to upper case names of letters, digits and underscores
+func getJournaldFieldName(key string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, key)
+
+	name = strings.TrimLeft(name, `_0123456789`)
+	if len(name) > 64 {
+		name = name[:64]
+	}
+
+	return name
+}
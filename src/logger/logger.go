@@ -1,58 +1,124 @@
 package logger
 
 import (
-	"log"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
 )
 
 type severity uint8
 
 const (
 	undefined severity = iota
-	errorSeverity
+	debugSeverity
 	infoSeverity
+	warnSeverity
+	errorSeverity
 )
 
 func (s severity) String() string {
 	switch s {
-	case errorSeverity:
-		return `ERROR`
+	case debugSeverity:
+		return `DEBUG`
 	case infoSeverity:
 		return `INFO`
+	case warnSeverity:
+		return `WARN`
+	case errorSeverity:
+		return `ERROR`
 	default:
 		return `undefined`
 	}
 }
 
+func parseSeverity(level string) (severity, error) {
+	for s := debugSeverity; s <= errorSeverity; s++ {
+		if strings.EqualFold(level, s.String()) {
+			return s, nil
+		}
+	}
+	if strings.EqualFold(level, `warning`) {
+		return warnSeverity, nil
+	}
+
+	return undefined, errors.New(`unknown log level "` + level + `"`)
+}
+
+// Fields are structured values attached to log records, like domain, serial, file or duration
+type Fields map[string]interface{}
+
+type record struct {
+	time     time.Time
+	module   string
+	severity severity
+	message  string
+	fields   Fields
+}
+
+const (
+	redactionMask = `******`
+	// secrets shorter than this are not redacted, as masking would garble unrelated text
+	minSecretLength = 4
+)
+
 type commonLogger struct {
-	loggers map[severity]*log.Logger
+	mutex    sync.Mutex
+	minLevel severity
+	format   format
+	// console is kept even if another sink is used, records the sink failed to write go there
+	console *consoleSink
+	sink    sink
+	now     func() time.Time
+	// secrets are replaced in messages and field values of every record
+	secrets []string
 }
 
 var mainLogger *commonLogger
 
-func (l *commonLogger) log(module string, severity severity, format string, args ...interface{}) {
-	prefix := ``
-	realArgs := make([]interface{}, 0)
-	if module != `` {
-		prefix += `[%s] `
-		realArgs = append(realArgs, module)
+func (l *commonLogger) log(module string, severity severity, fields Fields, format string, args ...interface{}) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if severity < l.minLevel {
+		return
 	}
-	if severity != undefined {
-		prefix += `[%s] `
-		realArgs = append(realArgs, severity.String())
 
+	r := &record{
+		time:     l.now(),
+		module:   module,
+		severity: severity,
+		message:  l.redact(fmt.Sprintf(format, args...)),
+		fields:   l.redactFields(fields),
 	}
-	loggr, exist := l.loggers[severity]
-	if !exist || loggr == nil {
-		return
+
+	err := l.sink.write(r, l.format)
+	if err != nil && l.sink != sink(l.console) {
+		_ = l.console.write(r, l.format)
 	}
-	realArgs = append(realArgs, args...)
-	loggr.Printf(prefix+format, realArgs...)
 }
 
-func (l *commonLogger) Infof(module string, format string, args ...interface{}) {
-	l.log(module, infoSeverity, format, args...)
+func (l *commonLogger) redact(text string) string {
+	for _, secret := range l.secrets {
+		text = strings.ReplaceAll(text, secret, redactionMask)
+	}
+	return text
 }
 
-func (l *commonLogger) Errorf(module string, format string, args ...interface{}) {
-	l.log(module, errorSeverity, format, args...)
+func (l *commonLogger) redactFields(fields Fields) Fields {
+	if len(l.secrets) < 1 || len(fields) < 1 {
+		return fields
+	}
+
+	redacted := make(Fields, len(fields))
+	for key, value := range fields {
+		if text, ok := normalizeValue(value).(string); ok {
+			redacted[key] = l.redact(text)
+			continue
+		}
+		redacted[key] = value
+	}
+
+	return redacted
 }
@@ -0,0 +1,118 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testTime = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+func newTestLogger(minLevel severity, outputFormat format) (*commonLogger, *bytes.Buffer) {
+	buffer := &bytes.Buffer{}
+	console := &consoleSink{
+		outputs: map[severity]io.Writer{
+			debugSeverity: buffer,
+			infoSeverity:  buffer,
+			warnSeverity:  buffer,
+			errorSeverity: buffer,
+		},
+	}
+	return &commonLogger{
+		minLevel: minLevel,
+		format:   outputFormat,
+		console:  console,
+		sink:     console,
+		now: func() time.Time {
+			return testTime
+		},
+	}, buffer
+}
+
+func TestLogger_MinLevel(t *testing.T) {
+	common, buffer := newTestLogger(warnSeverity, textFormat)
+	loggr := &logger{module: `test`, mainLogger: common}
+
+	loggr.Debugf(`debug`)
+	loggr.Infof(`info`)
+	loggr.Warnf(`warn`)
+	loggr.Errorf(`error`)
+
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf(`expected 2 records above min level, got %d`, len(lines))
+	}
+
+	if lines[0] != `2024/01/02 03:04:05 [test] [WARN] warn` {
+		t.Fatalf(`wrong text record "%s"`, lines[0])
+	}
+}
+
+func TestLogger_JSONFields(t *testing.T) {
+	common, buffer := newTestLogger(debugSeverity, jsonFormat)
+	loggr := (&logger{module: `test`, mainLogger: common}).With(Fields{`domain`: `example.com`})
+
+	loggr.With(Fields{`duration`: 1500 * time.Millisecond, `message`: `ignored`}).Infof(`renewed %s`, `ok`)
+
+	values := make(map[string]interface{})
+	err := json.Unmarshal(buffer.Bytes(), &values)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]interface{}{
+		`time`:     `2024-01-02T03:04:05Z`,
+		`level`:    `info`,
+		`module`:   `test`,
+		`message`:  `renewed ok`,
+		`domain`:   `example.com`,
+		`duration`: `1.5s`,
+	}
+	for key, value := range expected {
+		if values[key] != value {
+			t.Fatalf(`field "%s" is "%v", expected "%v"`, key, values[key], value)
+		}
+	}
+}
+
+func TestLogger_TextFields(t *testing.T) {
+	common, buffer := newTestLogger(debugSeverity, textFormat)
+	loggr := (&logger{module: `test`, mainLogger: common}).With(Fields{`file`: `/etc/ssl/my cert.pem`, `serial`: `ab01`})
+
+	loggr.Debugf(`saved`)
+
+	if buffer.String() != "2024/01/02 03:04:05 [test] [DEBUG] saved file=\"/etc/ssl/my cert.pem\" serial=ab01\n" {
+		t.Fatalf(`wrong text record "%s"`, buffer.String())
+	}
+}
+
+func TestParseSeverity(t *testing.T) {
+	level, err := parseSeverity(`Warning`)
+	if err != nil || level != warnSeverity {
+		t.Fatal(`warning level was not parsed`)
+	}
+
+	_, err = parseSeverity(`verbose`)
+	if err == nil {
+		t.Fatal(`unknown level was accepted`)
+	}
+}
+
+func TestLogger_Redaction(t *testing.T) {
+	common, buffer := newTestLogger(debugSeverity, textFormat)
+	common.secrets = []string{`hunter22`}
+	loggr := (&logger{module: `test`, mainLogger: common}).With(Fields{`url`: `https://hooks.example.com/hunter22`, `count`: 2})
+
+	loggr.Errorf(`login with "%s" failed`, `hunter22`)
+
+	if strings.Contains(buffer.String(), `hunter22`) {
+		t.Fatalf(`secret leaked to log "%s"`, buffer.String())
+	}
+
+	if !strings.Contains(buffer.String(), `count=2`) {
+		t.Fatal(`non secret fields are lost`)
+	}
+}
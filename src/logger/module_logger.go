@@ -1,23 +1,58 @@
 package logger
 
+type Logger interface {
+	Debugf(format string, args ...interface{})
+	Infof(format string, args ...interface{})
+	Warnf(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+	Error(err error)
+	With(fields Fields) Logger
+}
+
 type logger struct {
 	module     string
+	fields     Fields
 	mainLogger *commonLogger
 }
 
+func (l *logger) Debugf(format string, args ...interface{}) {
+	l.mainLogger.log(l.module, debugSeverity, l.fields, format, args...)
+}
+
 func (l *logger) Infof(format string, args ...interface{}) {
-	l.mainLogger.Infof(l.module, format, args...)
+	l.mainLogger.log(l.module, infoSeverity, l.fields, format, args...)
+}
+
+func (l *logger) Warnf(format string, args ...interface{}) {
+	l.mainLogger.log(l.module, warnSeverity, l.fields, format, args...)
 }
 
 func (l *logger) Errorf(format string, args ...interface{}) {
-	l.mainLogger.Errorf(l.module, format, args...)
+	l.mainLogger.log(l.module, errorSeverity, l.fields, format, args...)
 }
 
 func (l *logger) Error(err error) {
 	l.Errorf(`%s`, err)
 }
 
-func Make(module string) *logger {
+// With returns logger adding fields to every record, fields of the parent logger are kept
+func (l *logger) With(fields Fields) Logger {
+	merged := make(Fields, len(l.fields)+len(fields))
+	for key, value := range l.fields {
+		merged[key] = value
+	}
+	for key, value := range fields {
+		merged[key] = value
+	}
+
+	return &logger{
+		module:     l.module,
+		fields:     merged,
+		mainLogger: l.mainLogger,
+	}
+}
+
+func Make(module string) Logger {
 	return &logger{
 		module:     module,
 		mainLogger: mainLogger,
@@ -0,0 +1,111 @@
+package logger
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+const (
+	SinkConsole  = `console`
+	SinkJournald = `journald`
+	SinkSyslog   = `syslog`
+)
+
+// SinkSettings select where records are written, console (stdout and stderr) is used by default
+type SinkSettings struct {
+	Type string
+	// Network is unix, udp or tcp for syslog
+	Network string
+	// Address is syslog host:port or socket path, journald socket path
+	Address  string
+	Facility string
+	// Tag is syslog app name and journald identifier, executable name by default
+	Tag string
+}
+
+type sink interface {
+	write(r *record, f format) error
+	close() error
+}
+
+// consoleSink writes debug and info records to stdout, warn and error ones to stderr
+type consoleSink struct {
+	outputs map[severity]io.Writer
+}
+
+func newConsoleSink() *consoleSink {
+	return &consoleSink{
+		outputs: map[severity]io.Writer{
+			debugSeverity: os.Stdout,
+			infoSeverity:  os.Stdout,
+			warnSeverity:  os.Stderr,
+			errorSeverity: os.Stderr,
+		},
+	}
+}
+
+func (s *consoleSink) write(r *record, f format) error {
+	output, exist := s.outputs[r.severity]
+	if !exist || output == nil {
+		return nil
+	}
+
+	_, err := output.Write(f.encode(r))
+	return err
+}
+
+func (s *consoleSink) close() error {
+	return nil
+}
+
+func ValidateSinkSettings(settings SinkSettings) error {
+	switch settings.Type {
+	case ``, SinkConsole, SinkJournald:
+		return nil
+	case SinkSyslog:
+		_, err := parseFacility(settings.Facility)
+		if err != nil {
+			return err
+		}
+		return validateSyslogNetwork(settings.Network, settings.Address)
+	}
+
+	return errors.New(`unknown log sink "` + settings.Type + `"`)
+}
+
+func newSink(settings SinkSettings, console *consoleSink) (sink, error) {
+	err := ValidateSinkSettings(settings)
+	if err != nil {
+		return nil, err
+	}
+
+	tag := settings.Tag
+	if tag == `` {
+		tag = filepath.Base(os.Args[0])
+	}
+
+	switch settings.Type {
+	case SinkJournald:
+		return newJournaldSink(settings.Address, tag)
+	case SinkSyslog:
+		return newSyslogSink(settings.Network, settings.Address, settings.Facility, tag)
+	}
+
+	return console, nil
+}
+
+// priority maps severity to syslog levels, journald uses them as well
+func (s severity) priority() int {
+	switch s {
+	case debugSeverity:
+		return 7
+	case infoSeverity:
+		return 6
+	case warnSeverity:
+		return 4
+	default:
+		return 3
+	}
+}
@@ -0,0 +1,215 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var testRecord = &record{
+	time:     testTime,
+	module:   `main`,
+	severity: warnSeverity,
+	message:  "certificate renewed",
+	fields:   Fields{`domain`: `example.com`, `note`: `a "quoted" ]`},
+}
+
+func listenUnixgram(t *testing.T) (path string, conn *net.UnixConn) {
+	path = filepath.Join(t.TempDir(), `socket`)
+	conn, err := net.ListenUnixgram(`unixgram`, &net.UnixAddr{Name: path, Net: `unixgram`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+
+	return
+}
+
+func readDatagram(t *testing.T, conn net.PacketConn) string {
+	buffer := make([]byte, 65536)
+	n, _, err := conn.ReadFrom(buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(buffer[:n])
+}
+
+func TestJournaldSink(t *testing.T) {
+	path, conn := listenUnixgram(t)
+
+	journald, err := newJournaldSink(path, `ssl`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = journald.close()
+	}()
+
+	multiline := *testRecord
+	multiline.message = "first\nsecond"
+	err = journald.write(&multiline, textFormat)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	datagram := readDatagram(t, conn)
+
+	length := make([]byte, 8)
+	binary.LittleEndian.PutUint64(length, uint64(len("first\nsecond")))
+	expected := "MESSAGE\n" + string(length) + "first\nsecond\n" +
+		"PRIORITY=4\nSYSLOG_IDENTIFIER=ssl\nMODULE=main\nDOMAIN=example.com\nNOTE=a \"quoted\" ]\n"
+	if datagram != expected {
+		t.Fatalf(`wrong journald datagram %q`, datagram)
+	}
+}
+
+const expectedSyslogBody = `[fields@32473 domain="example.com" note="a \"quoted\" \]"] certificate renewed`
+
+func checkSyslogMessage(t *testing.T, message string) {
+	// <PRI>VERSION TIMESTAMP HOSTNAME APP-NAME PROCID MSGID STRUCTURED-DATA MSG
+	parts := strings.SplitN(message, ` `, 7)
+	if len(parts) != 7 || parts[0] != `<28>1` || parts[1] != `2024-01-02T03:04:05Z` || parts[3] != `ssl` || parts[5] != `main` {
+		t.Fatalf(`wrong syslog header %q`, message)
+	}
+
+	if parts[6] != expectedSyslogBody {
+		t.Fatalf(`wrong syslog body %q`, parts[6])
+	}
+}
+
+func TestSyslogSink_Unix(t *testing.T) {
+	path, conn := listenUnixgram(t)
+
+	syslog, err := newSyslogSink(`unix`, path, `daemon`, `ssl`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = syslog.close()
+	}()
+
+	err = syslog.write(testRecord, textFormat)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checkSyslogMessage(t, readDatagram(t, conn))
+}
+
+func TestSyslogSink_UDP(t *testing.T) {
+	conn, err := net.ListenPacket(`udp`, `127.0.0.1:0`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	syslog, err := newSyslogSink(`udp`, conn.LocalAddr().String(), `daemon`, `ssl`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = syslog.close()
+	}()
+
+	err = syslog.write(testRecord, textFormat)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checkSyslogMessage(t, readDatagram(t, conn))
+}
+
+func TestSyslogSink_TCP(t *testing.T) {
+	listener, err := net.Listen(`tcp`, `127.0.0.1:0`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = listener.Close()
+	}()
+
+	received := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer func() {
+			_ = conn.Close()
+		}()
+
+		reader := bufio.NewReader(conn)
+		for i := 0; i < 2; i++ {
+			var length int
+			_, err = fmt.Fscanf(reader, "%d ", &length)
+			if err != nil {
+				return
+			}
+			message := make([]byte, length)
+			_, err = io.ReadFull(reader, message)
+			if err != nil {
+				return
+			}
+			received <- string(message)
+		}
+	}()
+
+	syslog, err := newSyslogSink(`tcp`, listener.Addr().String(), `daemon`, `ssl`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = syslog.close()
+	}()
+
+	for i := 0; i < 2; i++ {
+		err = syslog.write(testRecord, textFormat)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkSyslogMessage(t, <-received)
+	}
+}
+
+func TestValidateSinkSettings(t *testing.T) {
+	invalid := []SinkSettings{
+		{Type: `file`},
+		{Type: SinkSyslog, Network: `tcp`},
+		{Type: SinkSyslog, Network: `sctp`, Address: `localhost:514`},
+		{Type: SinkSyslog, Facility: `local9`},
+	}
+	for _, settings := range invalid {
+		if ValidateSinkSettings(settings) == nil {
+			t.Fatalf(`invalid settings %+v were accepted`, settings)
+		}
+	}
+}
+
+func TestCommonLogger_SinkFallback(t *testing.T) {
+	common, buffer := newTestLogger(debugSeverity, textFormat)
+	path, conn := listenUnixgram(t)
+
+	journald, err := newJournaldSink(path, `ssl`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	common.sink = journald
+	_ = conn.Close()
+
+	common.log(`main`, infoSeverity, nil, `lost`)
+
+	if !bytes.Contains(buffer.Bytes(), []byte(`[main] [INFO] lost`)) {
+		t.Fatal(`record was not written to console after sink failure`)
+	}
+}
@@ -0,0 +1,194 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultSyslogSocket   = `/dev/log`
+	defaultSyslogFacility = `daemon`
+	// syslogStructuredDataID uses example enterprise number reserved for documentation by RFC 5612
+	syslogStructuredDataID = `fields@32473`
+)
+
+var syslogFacilities = map[string]int{
+	`kern`: 0, `user`: 1, `mail`: 2, `daemon`: 3, `auth`: 4, `syslog`: 5, `lpr`: 6, `news`: 7,
+	`uucp`: 8, `cron`: 9, `authpriv`: 10, `ftp`: 11,
+	`local0`: 16, `local1`: 17, `local2`: 18, `local3`: 19, `local4`: 20, `local5`: 21, `local6`: 22, `local7`: 23,
+}
+
+// syslogSink writes RFC 5424 messages, tcp ones are framed with octet counting from RFC 6587
+type syslogSink struct {
+	network  string
+	address  string
+	facility int
+	tag      string
+	hostname string
+	conn     net.Conn
+}
+
+func parseFacility(name string) (int, error) {
+	if name == `` {
+		name = defaultSyslogFacility
+	}
+
+	facility, exists := syslogFacilities[strings.ToLower(name)]
+	if !exists {
+		return 0, errors.New(`unknown syslog facility "` + name + `"`)
+	}
+
+	return facility, nil
+}
+
+func validateSyslogNetwork(network string, address string) error {
+	switch network {
+	case ``, `unix`:
+		return nil
+	case `udp`, `tcp`:
+		if address == `` {
+			return errors.New(`syslog address is required for ` + network)
+		}
+		return nil
+	}
+
+	return errors.New(`unknown syslog network "` + network + `"`)
+}
+
+func newSyslogSink(network string, address string, facilityName string, tag string) (s *syslogSink, err error) {
+	facility, err := parseFacility(facilityName)
+	if err != nil {
+		return
+	}
+
+	if network == `` || network == `unix` {
+		network = `unixgram`
+		if address == `` {
+			address = defaultSyslogSocket
+		}
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil || hostname == `` {
+		hostname = `-`
+	}
+
+	s = &syslogSink{
+		network:  network,
+		address:  address,
+		facility: facility,
+		tag:      tag,
+		hostname: hostname,
+	}
+
+	err = s.connect()
+
+	return
+}
+
+func (s *syslogSink) connect() (err error) {
+	s.conn, err = net.DialTimeout(s.network, s.address, 5*time.Second)
+	return
+}
+
+// write reconnects once, so restarted syslog servers do not lose the sink
+func (s *syslogSink) write(r *record, _ format) (err error) {
+	message := s.encode(r)
+	if s.network == `tcp` {
+		message = []byte(strconv.Itoa(len(message)) + ` ` + string(message))
+	}
+
+	if s.conn != nil {
+		_, err = s.conn.Write(message)
+		if err == nil {
+			return
+		}
+		_ = s.conn.Close()
+	}
+
+	err = s.connect()
+	if err != nil {
+		return
+	}
+
+	_, err = s.conn.Write(message)
+
+	return
+}
+
+func (s *syslogSink) close() error {
+	if s.conn == nil {
+		return nil
+	}
+	return s.conn.Close()
+}
+
+func (s *syslogSink) encode(r *record) []byte {
+	msgID := r.module
+	if msgID == `` {
+		msgID = `-`
+	}
+
+	return []byte(fmt.Sprintf(
+		`<%d>1 %s %s %s %d %s %s %s`,
+		s.facility*8+r.severity.priority(),
+		r.time.Format(time.RFC3339Nano),
+		getSyslogHeaderValue(s.hostname, 255),
+		getSyslogHeaderValue(s.tag, 48),
+		os.Getpid(),
+		getSyslogHeaderValue(msgID, 32),
+		encodeStructuredData(r.fields),
+		strings.TrimRight(r.message, "\n"),
+	))
+}
+
+// getSyslogHeaderValue keeps printable ascii without spaces, as header fields require
+func getSyslogHeaderValue(value string, maxLength int) string {
+	value = strings.Map(func(r rune) rune {
+		if r < 33 || r > 126 {
+			return '_'
+		}
+		return r
+	}, value)
+	if len(value) > maxLength {
+		value = value[:maxLength]
+	}
+	if value == `` {
+		return `-`
+	}
+
+	return value
+}
+
+func encodeStructuredData(fields Fields) string {
+	if len(fields) < 1 {
+		return `-`
+	}
+
+	builder := &strings.Builder{}
+	builder.WriteString(`[` + syslogStructuredDataID)
+	for _, key := range getSortedKeys(fields) {
+		name := strings.Map(func(r rune) rune {
+			if r < 33 || r > 126 || r == '=' || r == ']' || r == '"' {
+				return '_'
+			}
+			return r
+		}, key)
+		if len(name) > 32 {
+			name = name[:32]
+		}
+
+		value := fmt.Sprint(normalizeValue(fields[key]))
+		value = strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`).Replace(value)
+
+		builder.WriteString(` ` + name + `="` + value + `"`)
+	}
+	builder.WriteString(`]`)
+
+	return builder.String()
+}
@@ -10,8 +10,12 @@ const (
 	OK ExitCode = iota
 	NO_CHANGE
 	ERROR
+	HOOK_ERROR
+	PREFLIGHT_ERROR
+	RATE_LIMITED
+	VERIFY_ERROR
 )
 
 func main() {
-	os.Exit(int(wrapper()))
+	os.Exit(int(wrapper(os.Args[1:])))
 }
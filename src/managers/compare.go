@@ -33,7 +33,7 @@ func CertsBundlesEqual(certs1bundle, certs2bundle []*x509.Certificate) bool {
 		return false
 	}
 	for i := range certs1bundle {
-		if !certs1bundle[i].Equal(certs1bundle[i]) {
+		if !certs1bundle[i].Equal(certs2bundle[i]) {
 			return false
 		}
 	}
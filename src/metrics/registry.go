@@ -0,0 +1,220 @@
+package metrics
+
+import (
+	"bufio"
+	"io"
+	"math"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	kindGauge     = `gauge`
+	kindCounter   = `counter`
+	kindHistogram = `histogram`
+)
+
+const labelValuesSeparator = "\xff"
+
+// Registry keeps metric families and writes them in Prometheus text exposition format
+type Registry struct {
+	mu       sync.Mutex
+	families []*family
+}
+
+type family struct {
+	name       string
+	help       string
+	kind       string
+	labelNames []string
+	buckets    []float64
+	series     map[string]*series
+}
+
+type series struct {
+	labelValues  []string
+	value        float64
+	bucketCounts []uint64
+	count        uint64
+}
+
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+func (r *Registry) register(name, help, kind string, buckets []float64, labelNames []string) *family {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	f := &family{
+		name:       name,
+		help:       help,
+		kind:       kind,
+		labelNames: labelNames,
+		buckets:    buckets,
+		series:     make(map[string]*series),
+	}
+	r.families = append(r.families, f)
+
+	return f
+}
+
+// getSeries must be called with registry locked
+func (f *family) getSeries(labelValues []string) *series {
+	values := make([]string, len(f.labelNames))
+	copy(values, labelValues)
+
+	key := strings.Join(values, labelValuesSeparator)
+	s, exists := f.series[key]
+	if !exists {
+		s = &series{
+			labelValues:  values,
+			bucketCounts: make([]uint64, len(f.buckets)),
+		}
+		f.series[key] = s
+	}
+
+	return s
+}
+
+func (r *Registry) WriteTo(w io.Writer) (written int64, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	buffer := bufio.NewWriter(w)
+	counter := &countingWriter{w: buffer}
+	for _, f := range r.families {
+		f.write(counter)
+	}
+	err = buffer.Flush()
+	written = counter.written
+
+	return
+}
+
+// WriteFile replaces file atomically, as node exporter textfile collector may read it any time
+func (r *Registry) WriteFile(filename string) (err error) {
+	tmp, err := os.CreateTemp(filepath.Dir(filename), filepath.Base(filename)+`.*.tmp`)
+	if err != nil {
+		return
+	}
+	defer func() {
+		if err != nil {
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+
+	_, err = r.WriteTo(tmp)
+	if err != nil {
+		_ = tmp.Close()
+		return
+	}
+
+	err = tmp.Chmod(0644)
+	if err != nil {
+		_ = tmp.Close()
+		return
+	}
+
+	err = tmp.Close()
+	if err != nil {
+		return
+	}
+
+	return os.Rename(tmp.Name(), filename)
+}
+
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set(`Content-Type`, `text/plain; version=0.0.4; charset=utf-8`)
+		_, _ = r.WriteTo(w)
+	})
+}
+
+func (f *family) write(w io.Writer) {
+	if len(f.series) < 1 {
+		return
+	}
+
+	_, _ = io.WriteString(w, `# HELP `+f.name+` `+escapeHelp(f.help)+"\n")
+	_, _ = io.WriteString(w, `# TYPE `+f.name+` `+f.kind+"\n")
+
+	keys := make([]string, 0, len(f.series))
+	for key := range f.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		s := f.series[key]
+		if f.kind != kindHistogram {
+			writeSample(w, f.name, f.labelNames, s.labelValues, ``, ``, s.value)
+			continue
+		}
+
+		cumulative := uint64(0)
+		for i, upperBound := range f.buckets {
+			cumulative += s.bucketCounts[i]
+			writeSample(w, f.name+`_bucket`, f.labelNames, s.labelValues, `le`, formatFloat(upperBound), float64(cumulative))
+		}
+		writeSample(w, f.name+`_bucket`, f.labelNames, s.labelValues, `le`, `+Inf`, float64(s.count))
+		writeSample(w, f.name+`_sum`, f.labelNames, s.labelValues, ``, ``, s.value)
+		writeSample(w, f.name+`_count`, f.labelNames, s.labelValues, ``, ``, float64(s.count))
+	}
+}
+
+func writeSample(w io.Writer, name string, labelNames, labelValues []string, extraLabelName, extraLabelValue string, value float64) {
+	pairs := make([]string, 0, len(labelNames)+1)
+	for i, labelName := range labelNames {
+		pairs = append(pairs, labelName+`="`+escapeLabelValue(labelValues[i])+`"`)
+	}
+	if extraLabelName != `` {
+		pairs = append(pairs, extraLabelName+`="`+extraLabelValue+`"`)
+	}
+
+	line := name
+	if len(pairs) > 0 {
+		line += `{` + strings.Join(pairs, `,`) + `}`
+	}
+
+	_, _ = io.WriteString(w, line+` `+formatFloat(value)+"\n")
+}
+
+func formatFloat(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return `+Inf`
+	case math.IsInf(value, -1):
+		return `-Inf`
+	case math.IsNaN(value):
+		return `NaN`
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+var helpReplacer = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+
+func escapeLabelValue(value string) string {
+	return labelValueReplacer.Replace(value)
+}
+
+func escapeHelp(help string) string {
+	return helpReplacer.Replace(help)
+}
+
+type countingWriter struct {
+	w       io.Writer
+	written int64
+}
+
+func (c *countingWriter) Write(p []byte) (n int, err error) {
+	n, err = c.w.Write(p)
+	c.written += int64(n)
+	return
+}
@@ -0,0 +1,68 @@
+package metrics
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestRegistry_WriteTo(t *testing.T) {
+	registry := NewRegistry()
+
+	gauge := registry.NewGauge(`test_gauge`, `gauge help`, `name`)
+	gauge.Set(1.5, `second`)
+	gauge.Set(2, "first \"quoted\"")
+
+	counter := registry.NewCounter(`test_total`, `counter help`)
+	counter.Inc()
+	counter.Add(2)
+
+	histogram := registry.NewHistogram(`test_seconds`, `histogram help`, []float64{1, 5}, `outcome`)
+	histogram.Observe(0.5, `ok`)
+	histogram.Observe(3, `ok`)
+	histogram.Observe(10, `ok`)
+
+	registry.NewGauge(`test_unused`, `not written without series`)
+
+	buffer := &bytes.Buffer{}
+	_, err := registry.WriteTo(buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reference := `# HELP test_gauge gauge help
+# TYPE test_gauge gauge
+test_gauge{name="first \"quoted\""} 2
+test_gauge{name="second"} 1.5
+# HELP test_total counter help
+# TYPE test_total counter
+test_total 3
+# HELP test_seconds histogram help
+# TYPE test_seconds histogram
+test_seconds_bucket{outcome="ok",le="1"} 1
+test_seconds_bucket{outcome="ok",le="5"} 2
+test_seconds_bucket{outcome="ok",le="+Inf"} 3
+test_seconds_sum{outcome="ok"} 13.5
+test_seconds_count{outcome="ok"} 3
+`
+
+	if buffer.String() != reference {
+		t.Fatalf("exposition does not match:\n%s", buffer.String())
+	}
+}
+
+func TestGauge_Reset(t *testing.T) {
+	registry := NewRegistry()
+	gauge := registry.NewGauge(`test_gauge`, `gauge help`, `name`)
+	gauge.Set(1, `first`)
+	gauge.Reset()
+
+	buffer := &bytes.Buffer{}
+	_, err := registry.WriteTo(buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if buffer.Len() > 0 {
+		t.Fatal(`series were not dropped`)
+	}
+}
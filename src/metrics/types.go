@@ -0,0 +1,71 @@
+package metrics
+
+type Gauge struct {
+	registry *Registry
+	family   *family
+}
+
+func (r *Registry) NewGauge(name, help string, labelNames ...string) *Gauge {
+	return &Gauge{registry: r, family: r.register(name, help, kindGauge, nil, labelNames)}
+}
+
+func (g *Gauge) Set(value float64, labelValues ...string) {
+	g.registry.mu.Lock()
+	defer g.registry.mu.Unlock()
+	g.family.getSeries(labelValues).value = value
+}
+
+// Reset drops all series, e.g. when set of observed objects changes
+func (g *Gauge) Reset() {
+	g.registry.mu.Lock()
+	defer g.registry.mu.Unlock()
+	g.family.series = make(map[string]*series)
+}
+
+type Counter struct {
+	registry *Registry
+	family   *family
+}
+
+func (r *Registry) NewCounter(name, help string, labelNames ...string) *Counter {
+	return &Counter{registry: r, family: r.register(name, help, kindCounter, nil, labelNames)}
+}
+
+func (c *Counter) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+func (c *Counter) Add(value float64, labelValues ...string) {
+	if value < 0 {
+		return
+	}
+	c.registry.mu.Lock()
+	defer c.registry.mu.Unlock()
+	c.family.getSeries(labelValues).value += value
+}
+
+type Histogram struct {
+	registry *Registry
+	family   *family
+}
+
+// NewHistogram expects buckets upper bounds in ascending order, "+Inf" bucket is added automatically
+func (r *Registry) NewHistogram(name, help string, buckets []float64, labelNames ...string) *Histogram {
+	bucketsCopy := make([]float64, len(buckets))
+	copy(bucketsCopy, buckets)
+	return &Histogram{registry: r, family: r.register(name, help, kindHistogram, bucketsCopy, labelNames)}
+}
+
+func (h *Histogram) Observe(value float64, labelValues ...string) {
+	h.registry.mu.Lock()
+	defer h.registry.mu.Unlock()
+	s := h.family.getSeries(labelValues)
+	for i, upperBound := range h.family.buckets {
+		if value <= upperBound {
+			s.bucketCounts[i]++
+			break
+		}
+	}
+	s.value += value
+	s.count++
+}
@@ -4,9 +4,12 @@ import (
 	"crypto"
 	"crypto/x509"
 	"errors"
+	"ssl/chain"
 	"ssl/config"
 	"ssl/keytype"
+	loglib "ssl/logger"
 	"ssl/managers"
+	"strconv"
 )
 
 type MultiBundleManager[T keytype.Private] struct {
@@ -76,21 +79,31 @@ func (m *MultiBundleManager[T]) Sync() (err error) {
 		return
 	}
 
-	keyComparable, ok := any(key).(interface{ Equal(crypto.PrivateKey) bool })
+	keyComparable, ok := any(key).(interface {
+		Equal(crypto.PrivateKey) bool
+		Public() crypto.PublicKey
+	})
 	if !ok {
 		return errors.New(`incomparable key`)
 	}
 
+	orderedCerts, orderErr := chain.BuildForPublicKey(keyComparable.Public(), certs)
+	if orderErr == nil && !managers.CertsBundlesEqual(orderedCerts, certs) {
+		logger.Infof(`certificate chain is misordered, rewriting it in all formats`)
+		return m.Set(key, orderedCerts)
+	}
+
 	if m.bundleManagers[0].NeedSync() {
-		err = m.bundleManagers[0].Set(key, certs)
+		err = m.resync(0, key, certs)
 		if err != nil {
 			return
 		}
 	}
 
-	for _, mgr := range m.bundleManagers[1:] {
+	for num := 1; num < len(m.bundleManagers); num++ {
+		mgr := m.bundleManagers[num]
 		if mgr.NeedSync() {
-			err = mgr.Set(key, certs)
+			err = m.resync(num, key, certs)
 			if err != nil {
 				return
 			}
@@ -100,7 +113,7 @@ func (m *MultiBundleManager[T]) Sync() (err error) {
 		curKey, _ := mgr.GetPrivateKey()
 
 		if curKey == nil && mgr.ShouldHavePrivateKey() {
-			err = mgr.Set(key, certs)
+			err = m.resync(num, key, certs)
 			if err != nil {
 				return
 			}
@@ -108,7 +121,7 @@ func (m *MultiBundleManager[T]) Sync() (err error) {
 		}
 
 		if curKey != nil && !keyComparable.Equal(curKey) {
-			err = mgr.Set(key, certs)
+			err = m.resync(num, key, certs)
 			if err != nil {
 				return
 			}
@@ -118,7 +131,7 @@ func (m *MultiBundleManager[T]) Sync() (err error) {
 		curCert, _ := mgr.GetCertificate()
 
 		if curCert == nil && mgr.ShouldHaveCertificate() {
-			err = mgr.Set(key, certs)
+			err = m.resync(num, key, certs)
 			if err != nil {
 				return
 			}
@@ -126,7 +139,7 @@ func (m *MultiBundleManager[T]) Sync() (err error) {
 		}
 
 		if curCert != nil && !certs[0].Equal(curCert) {
-			err = mgr.Set(key, certs)
+			err = m.resync(num, key, certs)
 			if err != nil {
 				return
 			}
@@ -136,7 +149,7 @@ func (m *MultiBundleManager[T]) Sync() (err error) {
 		curIntermediates, _ := mgr.GetIntermediates()
 
 		if curIntermediates == nil && mgr.ShouldHaveIntermediates() {
-			err = mgr.Set(key, certs)
+			err = m.resync(num, key, certs)
 			if err != nil {
 				return
 			}
@@ -144,7 +157,7 @@ func (m *MultiBundleManager[T]) Sync() (err error) {
 		}
 
 		if curIntermediates != nil && !managers.CertsBundlesEqual(certs[1:], curIntermediates) {
-			err = mgr.Set(key, certs)
+			err = m.resync(num, key, certs)
 			if err != nil {
 				return
 			}
@@ -156,6 +169,12 @@ func (m *MultiBundleManager[T]) Sync() (err error) {
 	return
 }
 
+func (m *MultiBundleManager[T]) resync(num int, key T, certs []*x509.Certificate) error {
+	appMetrics.syncMismatches.Inc(strconv.Itoa(num))
+	logger.With(loglib.Fields{`format`: num}).Debugf(`save format is out of sync, rewriting it`)
+	return m.bundleManagers[num].Set(key, certs)
+}
+
 func (m *MultiBundleManager[T]) GetPrivateKey() (T, error) {
 	return m.bundleManagers[0].GetPrivateKey()
 }
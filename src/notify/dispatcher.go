@@ -0,0 +1,68 @@
+package notify
+
+import (
+	"errors"
+)
+
+type Notifier interface {
+	Notify(message *Message) error
+}
+
+type target struct {
+	notifier Notifier
+	// events is empty if notifier is subscribed to all of them
+	events map[Event]bool
+}
+
+type Dispatcher struct {
+	targets []*target
+}
+
+func NewDispatcher(webhooks []WebhookSettings, emails []SMTPSettings) (dispatcher *Dispatcher, err error) {
+	dispatcher = &Dispatcher{}
+
+	for _, settings := range webhooks {
+		var webhook *Webhook
+		webhook, err = NewWebhook(settings)
+		if err != nil {
+			return
+		}
+		dispatcher.Add(webhook, settings.Events...)
+	}
+
+	for _, settings := range emails {
+		var mailer *SMTP
+		mailer, err = NewSMTP(settings)
+		if err != nil {
+			return
+		}
+		dispatcher.Add(mailer, settings.Events...)
+	}
+
+	return
+}
+
+func (d *Dispatcher) Add(notifier Notifier, events ...Event) {
+	subscribed := make(map[Event]bool)
+	for _, event := range events {
+		subscribed[event] = true
+	}
+
+	d.targets = append(d.targets, &target{notifier: notifier, events: subscribed})
+}
+
+// Notify sends message to every subscribed notifier, failed ones do not stop the others
+func (d *Dispatcher) Notify(message *Message) (errs []error) {
+	for _, t := range d.targets {
+		if len(t.events) > 0 && !t.events[message.Event] {
+			continue
+		}
+
+		err := t.notifier.Notify(message)
+		if err != nil {
+			errs = append(errs, errors.New(`notification failed: `+err.Error()))
+		}
+	}
+
+	return
+}
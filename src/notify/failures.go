@@ -0,0 +1,59 @@
+package notify
+
+import (
+	"encoding/json"
+	"errors"
+	"ssl/storage"
+	"time"
+)
+
+// Failures counts consecutive failed renewals, so "expiring" is sent only if renewal keeps failing
+type Failures struct {
+	Count int       `json:"count"`
+	Since time.Time `json:"since"`
+}
+
+// LoadFailures reads failures from store, empty store means renewal has not failed
+func LoadFailures(store storage.Byte) (failures *Failures, err error) {
+	failures = &Failures{}
+
+	data, err := store.Load()
+	if err != nil {
+		if errors.Is(err, storage.EmptyNode) {
+			err = nil
+		}
+		return
+	}
+	if len(data) < 1 {
+		return
+	}
+
+	err = json.Unmarshal(data, failures)
+	if err != nil {
+		return nil, errors.New(`failures state is corrupted: ` + err.Error())
+	}
+
+	return
+}
+
+func (f *Failures) Save(store storage.Byte) error {
+	data, err := json.MarshalIndent(f, ``, `  `)
+	if err != nil {
+		return err
+	}
+	return store.Save(data)
+}
+
+// Record adds a failure, first one starts the streak
+func (f *Failures) Record(now time.Time) {
+	if f.Count < 1 {
+		f.Since = now.UTC()
+	}
+	f.Count++
+}
+
+// Reset ends the streak after successful renewal
+func (f *Failures) Reset() {
+	f.Count = 0
+	f.Since = time.Time{}
+}
@@ -0,0 +1,66 @@
+package notify
+
+import (
+	"bytes"
+	"errors"
+	"text/template"
+	"time"
+)
+
+type Event string
+
+const (
+	EventSuccess  Event = `success`
+	EventFailure  Event = `failure`
+	EventExpiring Event = `expiring`
+)
+
+// Message is passed to templates, so field names are part of config format
+type Message struct {
+	Event    Event
+	Name     string
+	Domains  []string
+	Serial   string
+	NotAfter time.Time
+	DaysLeft int
+	Error    string
+}
+
+const defaultTextTemplate = `{{if eq .Event "success"}}Certificate {{.Name}} renewed, serial {{.Serial}}, valid until {{.NotAfter.Format "2006-01-02"}}` +
+	`{{else if eq .Event "failure"}}Certificate {{.Name}} renewal failed: {{.Error}}` +
+	`{{else}}Certificate {{.Name}} expires in {{.DaysLeft}} day(s) and renewal keeps failing: {{.Error}}{{end}}`
+
+const defaultSubjectTemplate = `[ssl] {{.Name}}: {{.Event}}`
+
+func IsKnownEvent(event Event) bool {
+	return event == EventSuccess || event == EventFailure || event == EventExpiring
+}
+
+func parseTemplate(name string, text string, defaultText string) (tmpl *template.Template, err error) {
+	if text == `` {
+		text = defaultText
+	}
+
+	tmpl, err = template.New(name).Option(`missingkey=error`).Parse(text)
+	if err != nil {
+		err = errors.New(name + ` template is invalid: ` + err.Error())
+	}
+
+	return
+}
+
+// ValidateTemplate checks template syntax, empty template means default one
+func ValidateTemplate(text string) error {
+	_, err := parseTemplate(`message`, text, defaultTextTemplate)
+	return err
+}
+
+func render(tmpl *template.Template, message *Message) (string, error) {
+	buffer := &bytes.Buffer{}
+	err := tmpl.Execute(buffer, message)
+	if err != nil {
+		return ``, err
+	}
+
+	return buffer.String(), nil
+}
@@ -0,0 +1,100 @@
+package notify
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// defaultSMTPPort is the message submission port, used if port is not set
+const defaultSMTPPort = 587
+
+type SMTPSettings struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+	To       []string
+	Subject  string
+	Template string
+	Events   []Event
+}
+
+type SMTP struct {
+	address  string
+	auth     smtp.Auth
+	from     string
+	to       []string
+	subject  *template.Template
+	template *template.Template
+}
+
+func NewSMTP(settings SMTPSettings) (mailer *SMTP, err error) {
+	if settings.Host == `` || settings.From == `` || len(settings.To) < 1 {
+		err = errors.New(`smtp host, from and to are required`)
+		return
+	}
+
+	subject, err := parseTemplate(`subject`, settings.Subject, defaultSubjectTemplate)
+	if err != nil {
+		return
+	}
+
+	tmpl, err := parseTemplate(`message`, settings.Template, defaultTextTemplate)
+	if err != nil {
+		return
+	}
+
+	port := settings.Port
+	if port == 0 {
+		port = defaultSMTPPort
+	}
+
+	mailer = &SMTP{
+		address:  net.JoinHostPort(settings.Host, strconv.Itoa(port)),
+		from:     settings.From,
+		to:       settings.To,
+		subject:  subject,
+		template: tmpl,
+	}
+
+	if settings.Username != `` {
+		mailer.auth = smtp.PlainAuth(``, settings.Username, settings.Password, settings.Host)
+	}
+
+	return
+}
+
+func (s *SMTP) Notify(message *Message) (err error) {
+	subject, err := render(s.subject, message)
+	if err != nil {
+		return
+	}
+
+	text, err := render(s.template, message)
+	if err != nil {
+		return
+	}
+
+	return smtp.SendMail(s.address, s.auth, s.from, s.to, s.compose(subject, text))
+}
+
+func (s *SMTP) compose(subject string, text string) []byte {
+	buffer := &bytes.Buffer{}
+	buffer.WriteString(`From: ` + s.from + "\r\n")
+	buffer.WriteString(`To: ` + strings.Join(s.to, `, `) + "\r\n")
+	buffer.WriteString(`Subject: ` + strings.ReplaceAll(subject, "\n", ` `) + "\r\n")
+	buffer.WriteString(`Date: ` + time.Now().Format(time.RFC1123Z) + "\r\n")
+	buffer.WriteString("MIME-Version: 1.0\r\n")
+	buffer.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	buffer.WriteString(strings.ReplaceAll(strings.ReplaceAll(text, "\r\n", "\n"), "\n", "\r\n"))
+	buffer.WriteString("\r\n")
+
+	return buffer.Bytes()
+}
@@ -0,0 +1,140 @@
+package notify
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"testing"
+)
+
+type receivedMail struct {
+	from string
+	to   []string
+	data string
+}
+
+// startSMTPServer accepts one plain text session, enough for net/smtp client without extensions
+func startSMTPServer(t *testing.T) (host string, port int, mails chan *receivedMail) {
+	listener, err := net.Listen(`tcp`, `127.0.0.1:0`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = listener.Close()
+	})
+
+	mails = make(chan *receivedMail, 1)
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer func() {
+			_ = conn.Close()
+		}()
+
+		reader := bufio.NewReader(conn)
+		reply := func(line string) {
+			_, _ = conn.Write([]byte(line + "\r\n"))
+		}
+
+		mail := &receivedMail{}
+		reply(`220 localhost ready`)
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			line = strings.TrimRight(line, "\r\n")
+			command := strings.ToUpper(line)
+
+			switch {
+			case strings.HasPrefix(command, `EHLO`), strings.HasPrefix(command, `HELO`):
+				reply(`250 localhost`)
+			case strings.HasPrefix(command, `MAIL FROM:`):
+				mail.from = strings.Trim(line[len(`MAIL FROM:`):], `<>`)
+				reply(`250 OK`)
+			case strings.HasPrefix(command, `RCPT TO:`):
+				mail.to = append(mail.to, strings.Trim(line[len(`RCPT TO:`):], `<>`))
+				reply(`250 OK`)
+			case command == `DATA`:
+				reply(`354 go ahead`)
+				data := &strings.Builder{}
+				for {
+					dataLine, err := reader.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if dataLine == ".\r\n" {
+						break
+					}
+					data.WriteString(dataLine)
+				}
+				mail.data = data.String()
+				reply(`250 OK`)
+			case command == `QUIT`:
+				reply(`221 bye`)
+				mails <- mail
+				return
+			default:
+				reply(`502 not implemented`)
+			}
+		}
+	}()
+
+	address := listener.Addr().(*net.TCPAddr)
+
+	return address.IP.String(), address.Port, mails
+}
+
+func TestSMTP_Notify(t *testing.T) {
+	host, port, mails := startSMTPServer(t)
+
+	mailer, err := NewSMTP(SMTPSettings{
+		Host:    host,
+		Port:    port,
+		From:    `ssl@example.com`,
+		To:      []string{`admin@example.com`},
+		Subject: `{{.Name}} {{.Event}}`,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = mailer.Notify(&Message{Event: EventExpiring, Name: `example.com`, DaysLeft: 3, Error: `timeout`})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mail := <-mails
+	if mail.from != `ssl@example.com` || len(mail.to) != 1 || mail.to[0] != `admin@example.com` {
+		t.Fatalf(`wrong envelope: %s -> %v`, mail.from, mail.to)
+	}
+
+	if !strings.Contains(mail.data, "Subject: example.com expiring\r\n") {
+		t.Fatal(`subject template was not used`)
+	}
+
+	expected := `Certificate example.com expires in 3 day(s) and renewal keeps failing: timeout`
+	if !strings.Contains(mail.data, expected) {
+		t.Fatalf(`wrong body: %s`, mail.data)
+	}
+}
+
+func TestNewSMTP_Required(t *testing.T) {
+	_, err := NewSMTP(SMTPSettings{Host: `localhost`, Port: 25})
+	if err == nil {
+		t.Fatal(`smtp without addresses was accepted`)
+	}
+}
+
+func TestNewSMTP_DefaultPort(t *testing.T) {
+	mailer, err := NewSMTP(SMTPSettings{Host: `localhost`, From: `ssl@example.com`, To: []string{`admin@example.com`}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mailer.address != `localhost:587` {
+		t.Fatalf(`port is not defaulted, address is "%s"`, mailer.address)
+	}
+}
@@ -0,0 +1,112 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"text/template"
+	"time"
+)
+
+const (
+	WebhookFormatGeneric = `generic`
+	WebhookFormatSlack   = `slack`
+)
+
+const webhookTimeout = 10 * time.Second
+
+type WebhookSettings struct {
+	URL      string
+	Format   string
+	Template string
+	Events   []Event
+}
+
+type Webhook struct {
+	url      string
+	format   string
+	template *template.Template
+	client   *http.Client
+}
+
+type genericPayload struct {
+	Event    Event     `json:"event"`
+	Name     string    `json:"name"`
+	Domains  []string  `json:"domains"`
+	Serial   string    `json:"serial,omitempty"`
+	NotAfter time.Time `json:"notAfter,omitempty"`
+	DaysLeft int       `json:"daysLeft"`
+	Error    string    `json:"error,omitempty"`
+	Text     string    `json:"text"`
+}
+
+type slackPayload struct {
+	Text string `json:"text"`
+}
+
+func NewWebhook(settings WebhookSettings) (webhook *Webhook, err error) {
+	format := settings.Format
+	if format == `` {
+		format = WebhookFormatGeneric
+	}
+	if format != WebhookFormatGeneric && format != WebhookFormatSlack {
+		err = errors.New(`unknown webhook format "` + format + `"`)
+		return
+	}
+
+	tmpl, err := parseTemplate(`message`, settings.Template, defaultTextTemplate)
+	if err != nil {
+		return
+	}
+
+	webhook = &Webhook{
+		url:      settings.URL,
+		format:   format,
+		template: tmpl,
+		client:   &http.Client{Timeout: webhookTimeout},
+	}
+
+	return
+}
+
+func (w *Webhook) Notify(message *Message) (err error) {
+	text, err := render(w.template, message)
+	if err != nil {
+		return
+	}
+
+	var payload any = slackPayload{Text: text}
+	if w.format == WebhookFormatGeneric {
+		payload = genericPayload{
+			Event:    message.Event,
+			Name:     message.Name,
+			Domains:  message.Domains,
+			Serial:   message.Serial,
+			NotAfter: message.NotAfter,
+			DaysLeft: message.DaysLeft,
+			Error:    message.Error,
+			Text:     text,
+		}
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return
+	}
+
+	response, err := w.client.Post(w.url, `application/json`, bytes.NewReader(body))
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = response.Body.Close()
+	}()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		err = errors.New(fmt.Sprintf(`webhook responded with status %d`, response.StatusCode))
+	}
+
+	return
+}
@@ -0,0 +1,131 @@
+package notify
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+var testMessage = &Message{
+	Event:   EventFailure,
+	Name:    `example.com`,
+	Domains: []string{`example.com`, `www.example.com`},
+	Error:   `order failed`,
+}
+
+func startWebhookServer(t *testing.T) (server *httptest.Server, payloads chan map[string]any) {
+	payloads = make(chan map[string]any, 10)
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload := make(map[string]any)
+		err := json.NewDecoder(r.Body).Decode(&payload)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		payloads <- payload
+	}))
+	t.Cleanup(server.Close)
+
+	return
+}
+
+func TestWebhook_Generic(t *testing.T) {
+	server, payloads := startWebhookServer(t)
+
+	webhook, err := NewWebhook(WebhookSettings{URL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = webhook.Notify(testMessage)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	payload := <-payloads
+	if payload[`event`] != string(EventFailure) || payload[`name`] != `example.com` || payload[`error`] != `order failed` {
+		t.Fatalf(`generic payload is wrong: %v`, payload)
+	}
+
+	if payload[`text`] != `Certificate example.com renewal failed: order failed` {
+		t.Fatalf(`default template rendered wrong text "%s"`, payload[`text`])
+	}
+}
+
+func TestWebhook_SlackTemplate(t *testing.T) {
+	server, payloads := startWebhookServer(t)
+
+	_, err := NewWebhook(WebhookSettings{
+		URL:      server.URL,
+		Format:   WebhookFormatSlack,
+		Template: `{{.Event}}: {{join .Domains}}`,
+	})
+	if err == nil {
+		t.Fatal(`unknown template function was accepted`)
+	}
+
+	webhook, err := NewWebhook(WebhookSettings{
+		URL:      server.URL,
+		Format:   WebhookFormatSlack,
+		Template: `{{.Event}}:{{range .Domains}} {{.}}{{end}}`,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = webhook.Notify(testMessage)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	payload := <-payloads
+	if len(payload) != 1 || payload[`text`] != `failure: example.com www.example.com` {
+		t.Fatalf(`slack payload is wrong: %v`, payload)
+	}
+}
+
+func TestWebhook_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	webhook, err := NewWebhook(WebhookSettings{URL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = webhook.Notify(testMessage)
+	if err == nil || !strings.Contains(err.Error(), `500`) {
+		t.Fatal(`error status was not reported`)
+	}
+}
+
+func TestDispatcher_Events(t *testing.T) {
+	server, payloads := startWebhookServer(t)
+
+	dispatcher, err := NewDispatcher([]WebhookSettings{{URL: server.URL, Events: []Event{EventSuccess}}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	errs := dispatcher.Notify(testMessage)
+	if len(errs) > 0 {
+		t.Fatal(errs[0])
+	}
+
+	if len(payloads) > 0 {
+		t.Fatal(`notifier got event it is not subscribed to`)
+	}
+
+	errs = dispatcher.Notify(&Message{Event: EventSuccess, Name: `example.com`})
+	if len(errs) > 0 {
+		t.Fatal(errs[0])
+	}
+
+	if len(payloads) != 1 {
+		t.Fatal(`subscribed event was not sent`)
+	}
+}
@@ -0,0 +1,119 @@
+package preflight
+
+import (
+	"errors"
+	"fmt"
+	"github.com/miekg/dns"
+	"strings"
+)
+
+const (
+	caaTagIssue     = `issue`
+	caaTagIssueWild = `issuewild`
+	caaTagIODEF     = `iodef`
+	// caaFlagCritical marks properties CA must understand to issue
+	caaFlagCritical = 128
+)
+
+// checkCAA finds the relevant CAA record set by climbing the domain tree (RFC 8659)
+// and checks that one of our CA identities may issue for the domain.
+func (c *Checker) checkCAA(domain string) error {
+	wildcard := strings.HasPrefix(domain, `*.`)
+	name := strings.TrimPrefix(domain, `*.`)
+
+	for ; name != ``; name = parentDomain(name) {
+		answer, _, err := c.resolver.query(name, dns.TypeCAA)
+		if err != nil {
+			return err
+		}
+
+		records := make([]*dns.CAA, 0, len(answer))
+		for _, record := range answer {
+			if caa, ok := record.(*dns.CAA); ok {
+				records = append(records, caa)
+			}
+		}
+
+		if len(records) > 0 {
+			return c.getCAARecordSetError(name, records, wildcard)
+		}
+	}
+
+	return nil
+}
+
+func (c *Checker) getCAARecordSetError(name string, records []*dns.CAA, wildcard bool) error {
+	issue := make([]string, 0, len(records))
+	issueWild := make([]string, 0, len(records))
+	for _, record := range records {
+		tag := strings.ToLower(record.Tag)
+		switch tag {
+		case caaTagIssue:
+			issue = append(issue, record.Value)
+		case caaTagIssueWild:
+			issueWild = append(issueWild, record.Value)
+		case caaTagIODEF:
+		default:
+			if record.Flag&caaFlagCritical != 0 {
+				return errors.New(fmt.Sprintf(`unknown critical tag "%s" in CAA records of "%s"`, record.Tag, name))
+			}
+		}
+	}
+
+	values := issue
+	if wildcard && len(issueWild) > 0 {
+		values = issueWild
+	}
+
+	// no property restricts issuance, e.g. only iodef is set
+	if len(values) < 1 {
+		return nil
+	}
+
+	accountMismatch := false
+	for _, value := range values {
+		issuer, parameters := parseCAAIssueValue(value)
+		if !c.isOurCA(issuer) {
+			continue
+		}
+
+		accountURI, bound := parameters[`accounturi`]
+		if !bound || accountURI == c.settings.AccountURI {
+			return nil
+		}
+		accountMismatch = true
+	}
+
+	if accountMismatch {
+		return errors.New(fmt.Sprintf(`CAA records of "%s" bind issuance to another account than "%s"`, name, c.settings.AccountURI))
+	}
+
+	return errors.New(fmt.Sprintf(`CAA records of "%s" do not permit issuance by %s (allowed: %s)`,
+		name, strings.Join(c.settings.CAAIdentities, `, `), strings.Join(values, `; `)))
+}
+
+func (c *Checker) isOurCA(issuer string) bool {
+	for _, identity := range c.settings.CAAIdentities {
+		if strings.EqualFold(issuer, identity) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseCAAIssueValue splits `letsencrypt.org; accounturi=https://...` into issuer domain and parameters
+func parseCAAIssueValue(value string) (issuer string, parameters map[string]string) {
+	parts := strings.Split(value, `;`)
+	issuer = strings.TrimSpace(parts[0])
+
+	parameters = make(map[string]string, len(parts)-1)
+	for _, part := range parts[1:] {
+		key, parameterValue, found := strings.Cut(strings.TrimSpace(part), `=`)
+		if !found {
+			continue
+		}
+		parameters[strings.ToLower(strings.TrimSpace(key))] = strings.TrimSpace(parameterValue)
+	}
+
+	return
+}
@@ -0,0 +1,97 @@
+package preflight
+
+import (
+	"errors"
+	"github.com/miekg/dns"
+	"net"
+	"strings"
+	"time"
+)
+
+const resolvConfFilename = `/etc/resolv.conf`
+
+type resolver struct {
+	address string
+	client  *dns.Client
+}
+
+func newResolver(address string, timeout time.Duration) (r *resolver, err error) {
+	if address == `` {
+		var clientConfig *dns.ClientConfig
+		clientConfig, err = dns.ClientConfigFromFile(resolvConfFilename)
+		if err != nil {
+			return
+		}
+		if len(clientConfig.Servers) < 1 {
+			err = errors.New(`no nameservers in "` + resolvConfFilename + `"`)
+			return
+		}
+		address = net.JoinHostPort(clientConfig.Servers[0], clientConfig.Port)
+	}
+
+	r = &resolver{
+		address: address,
+		client:  &dns.Client{Timeout: timeout},
+	}
+
+	return
+}
+
+// query returns answer records, nxdomain is reported as true and is not an error
+func (r *resolver) query(name string, recordType uint16) (answer []dns.RR, nxdomain bool, err error) {
+	message := new(dns.Msg)
+	message.SetQuestion(dns.Fqdn(name), recordType)
+	message.RecursionDesired = true
+
+	response, _, err := r.client.Exchange(message, r.address)
+	if err != nil {
+		return
+	}
+
+	switch response.Rcode {
+	case dns.RcodeSuccess:
+		answer = response.Answer
+	case dns.RcodeNameError:
+		nxdomain = true
+	default:
+		err = errors.New(dns.TypeToString[recordType] + ` lookup of "` + name + `" failed with ` + dns.RcodeToString[response.Rcode])
+	}
+
+	return
+}
+
+func (r *resolver) lookupAddresses(name string) (addresses []net.IP, err error) {
+	for _, recordType := range []uint16{dns.TypeA, dns.TypeAAAA} {
+		answer, nxdomain, err := r.query(name, recordType)
+		if err != nil {
+			return nil, err
+		}
+		if nxdomain {
+			return nil, errors.New(`domain "` + name + `" does not exist`)
+		}
+
+		for _, record := range answer {
+			switch record := record.(type) {
+			case *dns.A:
+				addresses = append(addresses, record.A)
+			case *dns.AAAA:
+				addresses = append(addresses, record.AAAA)
+			}
+		}
+	}
+
+	if len(addresses) < 1 {
+		err = errors.New(`domain "` + name + `" has no A or AAAA records`)
+	}
+
+	return
+}
+
+// parentDomain returns domain without its leftmost label, empty string for top level domain
+func parentDomain(name string) string {
+	_, parent, found := strings.Cut(strings.TrimSuffix(name, `.`), `.`)
+	if !found {
+		return ``
+	}
+	return parent
+}
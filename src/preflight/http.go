@@ -0,0 +1,141 @@
+package preflight
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const challengePathPrefix = `/.well-known/acme-challenge/`
+
+// maxTokenResponseBytes limits body read from self-fetch, real response is a short key authorization
+const maxTokenResponseBytes = 4096
+
+// checkHTTP serves a random token on the challenge port like HTTP-01 provider does
+// and fetches it through the public name of every resolved domain.
+// Error is returned only if test server can not be started.
+func (c *Checker) checkHTTP(results []*DomainResult) (err error) {
+	token, err := generateToken()
+	if err != nil {
+		return
+	}
+	content := token + `.preflight`
+
+	listener, err := net.Listen(`tcp`, net.JoinHostPort(``, strconv.Itoa(c.settings.ChallengePort)))
+	if err != nil {
+		return errors.New(`challenge port is not available for self-fetch: ` + err.Error())
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(challengePathPrefix+token, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(`Content-Type`, `text/plain`)
+		_, _ = w.Write([]byte(content))
+	})
+	server := &http.Server{Handler: mux}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	defer func() {
+		_ = server.Close()
+	}()
+
+	client := c.newSelfFetchClient()
+	for _, result := range results {
+		if len(result.Addresses) < 1 {
+			continue
+		}
+
+		err := c.fetchToken(client, result.Domain, token, content)
+		if err != nil {
+			result.addProblem(`http-01`, `%s`, err)
+		}
+	}
+
+	return nil
+}
+
+func (c *Checker) fetchToken(client *http.Client, domain string, token string, content string) error {
+	url := `http://` + net.JoinHostPort(domain, strconv.Itoa(c.publicHTTPPort)) + challengePathPrefix + token
+	if c.publicHTTPPort == defaultHTTPPort {
+		url = `http://` + domain + challengePathPrefix + token
+	}
+
+	response, err := client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = response.Body.Close()
+	}()
+
+	if response.StatusCode != http.StatusOK {
+		return errors.New(`GET ` + url + ` returned ` + response.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(response.Body, maxTokenResponseBytes))
+	if err != nil {
+		return err
+	}
+
+	if strings.TrimSpace(string(body)) != content {
+		return errors.New(`GET ` + url + ` returned unexpected content, request did not reach challenge server`)
+	}
+
+	return nil
+}
+
+// newSelfFetchClient resolves names with the configured resolver, so the fetch follows the same path as CA.
+// Like CA validation, redirects to https are followed without verifying certificate.
+func (c *Checker) newSelfFetchClient() *http.Client {
+	dialer := &net.Dialer{Timeout: c.settings.Timeout}
+
+	transport := &http.Transport{
+		Proxy: nil,
+		DialContext: func(ctx context.Context, network string, address string) (conn net.Conn, err error) {
+			host, port, err := net.SplitHostPort(address)
+			if err != nil {
+				return
+			}
+
+			addresses := []net.IP{net.ParseIP(host)}
+			if addresses[0] == nil {
+				addresses, err = c.resolver.lookupAddresses(host)
+				if err != nil {
+					return
+				}
+			}
+
+			for _, ip := range addresses {
+				conn, err = dialer.DialContext(ctx, network, net.JoinHostPort(ip.String(), port))
+				if err == nil {
+					return
+				}
+			}
+
+			return
+		},
+		TLSClientConfig:   &tls.Config{InsecureSkipVerify: true},
+		DisableKeepAlives: true,
+	}
+
+	return &http.Client{
+		Transport: transport,
+		Timeout:   c.settings.Timeout,
+	}
+}
+
+func generateToken() (string, error) {
+	data := make([]byte, 32)
+	_, err := rand.Read(data)
+	if err != nil {
+		return ``, err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
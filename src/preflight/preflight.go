@@ -0,0 +1,112 @@
+package preflight
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// defaultHTTPPort is the port CA connects to for HTTP-01 validation
+const defaultHTTPPort = 80
+
+type Settings struct {
+	// Resolver is "host:port" of DNS server, system resolver from /etc/resolv.conf is used if empty
+	Resolver string
+	// CAAIdentities are issuer domain names of our CA, e.g. "letsencrypt.org"
+	CAAIdentities []string
+	// AccountURI must match accounturi parameter of CAA records if they have it
+	AccountURI string
+	// ChallengePort is the local port of HTTP-01 challenge server
+	ChallengePort int
+	Timeout       time.Duration
+}
+
+// DomainResult lists problems of one domain, domain passed all checks if Problems is empty
+type DomainResult struct {
+	Domain    string
+	Addresses []net.IP
+	Problems  []string
+}
+
+func (r *DomainResult) addProblem(check string, format string, args ...interface{}) {
+	r.Problems = append(r.Problems, check+`: `+fmt.Sprintf(format, args...))
+}
+
+// Error is returned by Check if at least one domain has problems
+type Error struct {
+	Results []*DomainResult
+}
+
+func (e *Error) Error() string {
+	failed := make([]*DomainResult, 0, len(e.Results))
+	for _, result := range e.Results {
+		if len(result.Problems) > 0 {
+			failed = append(failed, result)
+		}
+	}
+
+	lines := []string{fmt.Sprintf(`preflight failed for %d of %d domains:`, len(failed), len(e.Results))}
+	for _, result := range failed {
+		for _, problem := range result.Problems {
+			lines = append(lines, `  `+result.Domain+`: `+problem)
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+type Checker struct {
+	settings Settings
+	resolver *resolver
+	// publicHTTPPort is where the self-fetch connects, it differs from ChallengePort behind port forwarding
+	publicHTTPPort int
+}
+
+func NewChecker(settings Settings) (checker *Checker, err error) {
+	resolver, err := newResolver(settings.Resolver, settings.Timeout)
+	if err != nil {
+		return
+	}
+
+	checker = &Checker{
+		settings:       settings,
+		resolver:       resolver,
+		publicHTTPPort: defaultHTTPPort,
+	}
+
+	return
+}
+
+// Check resolves every domain, checks its CAA records and fetches a test token through the public name.
+// Results are returned for all domains, err is *Error if some domain has problems.
+func (c *Checker) Check(domains []string) (results []*DomainResult, err error) {
+	results = make([]*DomainResult, 0, len(domains))
+	for _, domain := range domains {
+		result := &DomainResult{Domain: domain}
+		results = append(results, result)
+
+		result.Addresses, err = c.resolver.lookupAddresses(domain)
+		if err != nil {
+			result.addProblem(`dns`, `%s`, err)
+		}
+
+		err = c.checkCAA(domain)
+		if err != nil {
+			result.addProblem(`caa`, `%s`, err)
+		}
+	}
+
+	err = c.checkHTTP(results)
+	if err != nil {
+		return
+	}
+
+	for _, result := range results {
+		if len(result.Problems) > 0 {
+			return results, &Error{Results: results}
+		}
+	}
+
+	return
+}
@@ -0,0 +1,196 @@
+package preflight
+
+import (
+	"errors"
+	"github.com/miekg/dns"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testAccountURI = `https://acme.example/acct/1`
+
+// startResolver serves records from zone text on a local udp port
+func startResolver(t *testing.T, zone string) string {
+	records := make([]dns.RR, 0)
+	for _, line := range strings.Split(strings.TrimSpace(zone), "\n") {
+		record, err := dns.NewRR(strings.TrimSpace(line))
+		if err != nil {
+			t.Fatal(err)
+		}
+		records = append(records, record)
+	}
+
+	handler := dns.HandlerFunc(func(w dns.ResponseWriter, request *dns.Msg) {
+		response := new(dns.Msg)
+		response.SetReply(request)
+		question := request.Question[0]
+		known := false
+		for _, record := range records {
+			if strings.EqualFold(record.Header().Name, question.Name) {
+				known = true
+				if record.Header().Rrtype == question.Qtype {
+					response.Answer = append(response.Answer, record)
+				}
+			}
+		}
+		if !known && strings.HasPrefix(question.Name, `missing.`) {
+			response.Rcode = dns.RcodeNameError
+		}
+		_ = w.WriteMsg(response)
+	})
+
+	connection, err := net.ListenPacket(`udp`, `127.0.0.1:0`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := &dns.Server{PacketConn: connection, Handler: handler}
+	go func() {
+		_ = server.ActivateAndServe()
+	}()
+	t.Cleanup(func() {
+		_ = server.Shutdown()
+	})
+
+	return connection.LocalAddr().String()
+}
+
+func getFreePort(t *testing.T) int {
+	listener, err := net.Listen(`tcp`, `127.0.0.1:0`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = listener.Close()
+	}()
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+func newTestChecker(t *testing.T, zone string) *Checker {
+	port := getFreePort(t)
+	checker, err := NewChecker(Settings{
+		Resolver:      startResolver(t, zone),
+		CAAIdentities: []string{`letsencrypt.org`},
+		AccountURI:    testAccountURI,
+		ChallengePort: port,
+		Timeout:       2 * time.Second,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checker.publicHTTPPort = port
+
+	return checker
+}
+
+func getProblems(t *testing.T, results []*DomainResult, domain string) string {
+	for _, result := range results {
+		if result.Domain == domain {
+			return strings.Join(result.Problems, `; `)
+		}
+	}
+	t.Fatal(`no result for ` + domain)
+	return ``
+}
+
+func TestCheck(t *testing.T) {
+	checker := newTestChecker(t, `
+		ok.example.com. 60 IN A 127.0.0.1
+		example.com. 60 IN CAA 0 issue "letsencrypt.org"
+		other.example.com. 60 IN A 127.0.0.1
+		other.example.com. 60 IN CAA 0 issue "digicert.com"
+		bound.example.com. 60 IN A 127.0.0.1
+		bound.example.com. 60 IN CAA 0 issue "letsencrypt.org; accounturi=https://acme.example/acct/2"
+		mine.example.com. 60 IN A 127.0.0.1
+		mine.example.com. 60 IN CAA 0 issue "letsencrypt.org; accounturi=https://acme.example/acct/1"
+		critical.example.com. 60 IN A 127.0.0.1
+		critical.example.com. 60 IN CAA 128 tbs "unknown"
+	`)
+
+	results, err := checker.Check([]string{
+		`ok.example.com`,
+		`mine.example.com`,
+		`other.example.com`,
+		`bound.example.com`,
+		`critical.example.com`,
+		`missing.example.com`,
+	})
+
+	var preflightErr *Error
+	if !errors.As(err, &preflightErr) {
+		t.Fatal(`preflight error expected, got: `, err)
+	}
+
+	expected := map[string]string{
+		`ok.example.com`:       ``,
+		`mine.example.com`:     ``,
+		`other.example.com`:    `do not permit issuance`,
+		`bound.example.com`:    `another account`,
+		`critical.example.com`: `unknown critical tag`,
+		`missing.example.com`:  `does not exist`,
+	}
+	for domain, problem := range expected {
+		problems := getProblems(t, results, domain)
+		if problem == `` && problems != `` {
+			t.Fatal(domain + ` should pass, got: ` + problems)
+		}
+		if !strings.Contains(problems, problem) {
+			t.Fatal(domain + ` should fail with "` + problem + `", got: ` + problems)
+		}
+	}
+
+	if !strings.Contains(err.Error(), `preflight failed for 4 of 6 domains`) {
+		t.Fatal(`unexpected report: ` + err.Error())
+	}
+}
+
+func TestCheckPasses(t *testing.T) {
+	checker := newTestChecker(t, `
+		www.example.org. 60 IN CNAME example.org.
+		example.org. 60 IN A 127.0.0.1
+		example.org. 60 IN CAA 0 iodef "mailto:admin@example.org"
+	`)
+
+	results, err := checker.Check([]string{`example.org`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || len(results[0].Addresses) != 1 {
+		t.Fatal(`resolved address expected`)
+	}
+}
+
+func TestCheckHTTPFailure(t *testing.T) {
+	checker := newTestChecker(t, `
+		example.net. 60 IN A 127.0.0.1
+	`)
+
+	// nothing listens on the public port, e.g. port forwarding is missing
+	checker.publicHTTPPort = getFreePort(t)
+
+	results, err := checker.Check([]string{`example.net`})
+	if err == nil {
+		t.Fatal(`self-fetch error expected`)
+	}
+	if !strings.Contains(getProblems(t, results, `example.net`), `http-01:`) {
+		t.Fatal(`http-01 problem expected, got: ` + err.Error())
+	}
+}
+
+func TestCheckCAAWildcard(t *testing.T) {
+	checker := newTestChecker(t, `
+		example.com. 60 IN CAA 0 issue "letsencrypt.org"
+		example.com. 60 IN CAA 0 issuewild ";"
+	`)
+
+	err := checker.checkCAA(`www.example.com`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = checker.checkCAA(`*.example.com`)
+	if err == nil {
+		t.Fatal(`wildcard issuance should be forbidden`)
+	}
+}
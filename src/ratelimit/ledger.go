@@ -0,0 +1,162 @@
+package ratelimit
+
+import (
+	"encoding/json"
+	"errors"
+	"golang.org/x/net/publicsuffix"
+	"sort"
+	"ssl/storage"
+	"strings"
+	"time"
+)
+
+type Kind string
+
+const (
+	// KindIssued is a certificate issued for an order
+	KindIssued Kind = `issued`
+	// KindFailure is an order which failed validation of its identifiers
+	KindFailure Kind = `failure`
+)
+
+type Entry struct {
+	Time time.Time `json:"time"`
+	Kind Kind      `json:"kind"`
+	// CA separates staging and production limits
+	CA                string   `json:"ca"`
+	Identifiers       []string `json:"identifiers"`
+	RegisteredDomains []string `json:"registeredDomains"`
+}
+
+// Ledger keeps recent orders, so limits of CA can be applied locally before an order is placed
+type Ledger struct {
+	Entries []*Entry `json:"entries"`
+}
+
+// Load reads ledger from store, empty store gives empty ledger
+func Load(store storage.Byte) (ledger *Ledger, err error) {
+	ledger = &Ledger{Entries: make([]*Entry, 0)}
+
+	data, err := store.Load()
+	if err != nil {
+		if errors.Is(err, storage.EmptyNode) {
+			err = nil
+		}
+		return
+	}
+	if len(data) < 1 {
+		return
+	}
+
+	err = json.Unmarshal(data, ledger)
+	if err != nil {
+		return nil, errors.New(`ledger is corrupted: ` + err.Error())
+	}
+
+	return
+}
+
+func (l *Ledger) Save(store storage.Byte) error {
+	data, err := json.MarshalIndent(l, ``, `  `)
+	if err != nil {
+		return err
+	}
+	return store.Save(data)
+}
+
+// Record adds an entry and drops entries older than maxAge, so the ledger does not grow forever
+func (l *Ledger) Record(kind Kind, ca string, identifiers []string, now time.Time, maxAge time.Duration) {
+	identifiers = normalizeIdentifiers(identifiers)
+
+	entries := make([]*Entry, 0, len(l.Entries)+1)
+	for _, entry := range l.Entries {
+		if now.Sub(entry.Time) < maxAge {
+			entries = append(entries, entry)
+		}
+	}
+
+	l.Entries = append(entries, &Entry{
+		Time:              now.UTC(),
+		Kind:              kind,
+		CA:                ca,
+		Identifiers:       identifiers,
+		RegisteredDomains: getRegisteredDomains(identifiers),
+	})
+}
+
+// entriesWithin returns times of matching entries of the CA newer than period, oldest first
+func (l *Ledger) entriesWithin(ca string, kind Kind, period time.Duration, now time.Time, match func(entry *Entry) bool) []time.Time {
+	times := make([]time.Time, 0)
+	for _, entry := range l.Entries {
+		if entry.CA != ca || entry.Kind != kind || now.Sub(entry.Time) >= period {
+			continue
+		}
+		if match(entry) {
+			times = append(times, entry.Time)
+		}
+	}
+
+	sort.Slice(times, func(i, j int) bool {
+		return times[i].Before(times[j])
+	})
+
+	return times
+}
+
+// normalizeIdentifiers makes identifier sets comparable: lower case, sorted, without duplicates
+func normalizeIdentifiers(identifiers []string) []string {
+	unique := make(map[string]bool, len(identifiers))
+	normalized := make([]string, 0, len(identifiers))
+	for _, identifier := range identifiers {
+		identifier = strings.ToLower(strings.TrimSuffix(identifier, `.`))
+		if identifier == `` || unique[identifier] {
+			continue
+		}
+		unique[identifier] = true
+		normalized = append(normalized, identifier)
+	}
+	sort.Strings(normalized)
+
+	return normalized
+}
+
+func getRegisteredDomains(identifiers []string) []string {
+	domains := make([]string, 0, len(identifiers))
+	for _, identifier := range identifiers {
+		domains = append(domains, GetRegisteredDomain(identifier))
+	}
+	return normalizeIdentifiers(domains)
+}
+
+// GetRegisteredDomain returns public suffix plus one label, e.g. "example.co.uk" for "www.example.co.uk"
+func GetRegisteredDomain(identifier string) string {
+	identifier = strings.TrimPrefix(strings.ToLower(identifier), `*.`)
+
+	domain, err := publicsuffix.EffectiveTLDPlusOne(identifier)
+	if err != nil {
+		return identifier
+	}
+
+	return domain
+}
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+func equalStrings(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
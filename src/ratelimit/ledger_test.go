@@ -0,0 +1,111 @@
+package ratelimit
+
+import (
+	"errors"
+	"ssl/storage/memory"
+	"testing"
+	"time"
+)
+
+var testLimits = Limits{
+	CertificatesPerDomain: Limit{Count: 3, Period: 7 * 24 * time.Hour},
+	DuplicateCertificates: Limit{Count: 2, Period: 7 * 24 * time.Hour},
+	FailedValidations:     Limit{Count: 2, Period: time.Hour},
+}
+
+func TestCheckDuplicateCertificates(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	ledger := &Ledger{}
+
+	ledger.Record(KindIssued, `production`, []string{`www.example.com`, `example.com`}, now.Add(-48*time.Hour), testLimits.MaxPeriod())
+	ledger.Record(KindIssued, `production`, []string{`example.com`, `WWW.example.com`}, now.Add(-time.Hour), testLimits.MaxPeriod())
+
+	err := ledger.Check(testLimits, `production`, []string{`example.com`, `www.example.com`}, now)
+	var limitErr *Error
+	if !errors.As(err, &limitErr) {
+		t.Fatal(`duplicate certificate limit should be reached`)
+	}
+	if limitErr.Limit != `duplicate certificate` || !limitErr.NextAllowed.Equal(now.Add(5*24*time.Hour)) {
+		t.Fatal(`unexpected error: ` + err.Error())
+	}
+
+	err = ledger.Check(testLimits, `staging`, []string{`example.com`, `www.example.com`}, now)
+	if err != nil {
+		t.Fatal(`limits of other CA should not apply: ` + err.Error())
+	}
+
+	err = ledger.Check(testLimits, `production`, []string{`example.com`}, now)
+	if err != nil {
+		t.Fatal(`different identifier set is not a duplicate: ` + err.Error())
+	}
+}
+
+func TestCheckRegisteredDomain(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	ledger := &Ledger{}
+
+	for i, name := range []string{`a.example.co.uk`, `b.example.co.uk`, `c.example.co.uk`} {
+		ledger.Record(KindIssued, `production`, []string{name}, now.Add(-time.Duration(3-i)*time.Hour), testLimits.MaxPeriod())
+	}
+
+	err := ledger.Check(testLimits, `production`, []string{`d.example.co.uk`, `other.org`}, now)
+	var limitErr *Error
+	if !errors.As(err, &limitErr) || limitErr.Subject != `example.co.uk` {
+		t.Fatal(`registered domain limit should be reached for example.co.uk, got: `, err)
+	}
+	if !limitErr.NextAllowed.Equal(now.Add(-3 * time.Hour).Add(7 * 24 * time.Hour)) {
+		t.Fatal(`next attempt should be allowed when the oldest entry expires`)
+	}
+}
+
+func TestCheckFailedValidations(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	ledger := &Ledger{}
+
+	ledger.Record(KindFailure, `production`, []string{`example.com`}, now.Add(-90*time.Minute), testLimits.MaxPeriod())
+	ledger.Record(KindFailure, `production`, []string{`example.com`, `www.example.com`}, now.Add(-30*time.Minute), testLimits.MaxPeriod())
+
+	err := ledger.Check(testLimits, `production`, []string{`example.com`}, now)
+	if err != nil {
+		t.Fatal(`failure older than period should not be counted: ` + err.Error())
+	}
+
+	ledger.Record(KindFailure, `production`, []string{`example.com`}, now.Add(-10*time.Minute), testLimits.MaxPeriod())
+
+	err = ledger.Check(testLimits, `production`, []string{`example.com`}, now)
+	var limitErr *Error
+	if !errors.As(err, &limitErr) || !limitErr.NextAllowed.Equal(now.Add(30*time.Minute)) {
+		t.Fatal(`failed validation limit should be reached until the oldest failure expires, got: `, err)
+	}
+}
+
+func TestLedgerStorage(t *testing.T) {
+	now := time.Now()
+	store := memory.NewByteMemory()
+
+	ledger, err := Load(store)
+	if err != nil || len(ledger.Entries) != 0 {
+		t.Fatal(`empty store should give empty ledger`)
+	}
+
+	ledger.Record(KindIssued, `production`, []string{`old.example.com`}, now.Add(-10*24*time.Hour), testLimits.MaxPeriod())
+	ledger.Record(KindIssued, `production`, []string{`example.com`}, now, testLimits.MaxPeriod())
+	err = ledger.Save(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ledger, err = Load(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ledger.Entries) != 1 || ledger.Entries[0].RegisteredDomains[0] != `example.com` {
+		t.Fatal(`entries older than max period should be dropped`)
+	}
+
+	_ = store.Save([]byte(`{`))
+	_, err = Load(store)
+	if err == nil {
+		t.Fatal(`corrupted ledger should not be loaded`)
+	}
+}
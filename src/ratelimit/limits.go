@@ -0,0 +1,109 @@
+package ratelimit
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type Limit struct {
+	// Count of entries allowed within Period, zero disables the limit
+	Count  int
+	Period time.Duration
+}
+
+type Limits struct {
+	// CertificatesPerDomain counts certificates issued per registered domain
+	CertificatesPerDomain Limit
+	// DuplicateCertificates counts certificates issued for the exact identifier set
+	DuplicateCertificates Limit
+	// FailedValidations counts orders failed validation per identifier
+	FailedValidations Limit
+}
+
+// MaxPeriod is how long entries have to be kept in the ledger
+func (l Limits) MaxPeriod() time.Duration {
+	maxPeriod := time.Duration(0)
+	for _, limit := range []Limit{l.CertificatesPerDomain, l.DuplicateCertificates, l.FailedValidations} {
+		if limit.Period > maxPeriod {
+			maxPeriod = limit.Period
+		}
+	}
+	return maxPeriod
+}
+
+// Error tells which limit a new order would exceed and when the next one is allowed
+type Error struct {
+	Limit string
+	// Subject is the identifier set, registered domain or identifier the limit is counted for
+	Subject     string
+	Count       int
+	Period      time.Duration
+	NextAllowed time.Time
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf(`%s limit reached: %d within %s for "%s", next order is allowed at %s`,
+		e.Limit, e.Count, e.Period, e.Subject, e.NextAllowed.Local().Format(time.RFC3339))
+}
+
+// Check returns *Error if an order for identifiers would exceed one of the limits.
+// The limit allowing the latest next attempt is reported if several are exceeded.
+func (l *Ledger) Check(limits Limits, ca string, identifiers []string, now time.Time) (err error) {
+	identifiers = normalizeIdentifiers(identifiers)
+	var limitErr *Error
+
+	report := func(name string, subject string, limit Limit, times []time.Time) {
+		if limit.Count < 1 || len(times) < limit.Count {
+			return
+		}
+		// the oldest entry which has to expire to get below the limit
+		nextAllowed := times[len(times)-limit.Count].Add(limit.Period)
+		if limitErr == nil || nextAllowed.After(limitErr.NextAllowed) {
+			limitErr = &Error{
+				Limit:       name,
+				Subject:     subject,
+				Count:       len(times),
+				Period:      limit.Period,
+				NextAllowed: nextAllowed,
+			}
+		}
+	}
+
+	report(`duplicate certificate`, strings.Join(identifiers, `,`), limits.DuplicateCertificates,
+		l.entriesWithin(ca, KindIssued, limits.DuplicateCertificates.Period, now, func(entry *Entry) bool {
+			return equalStrings(entry.Identifiers, identifiers)
+		}))
+
+	for _, domain := range getRegisteredDomains(identifiers) {
+		report(`certificates per registered domain`, domain, limits.CertificatesPerDomain,
+			l.entriesWithin(ca, KindIssued, limits.CertificatesPerDomain.Period, now, func(entry *Entry) bool {
+				return containsString(entry.RegisteredDomains, domain)
+			}))
+	}
+
+	for _, identifier := range identifiers {
+		report(`failed validation`, identifier, limits.FailedValidations,
+			l.entriesWithin(ca, KindFailure, limits.FailedValidations.Period, now, func(entry *Entry) bool {
+				return containsString(entry.Identifiers, identifier)
+			}))
+	}
+
+	if limitErr != nil {
+		return limitErr
+	}
+
+	return nil
+}
+
+// Validate returns error for limits which can not be counted, e.g. without period
+func (l Limit) Validate(name string) error {
+	if l.Count < 0 {
+		return errors.New(name + ` count must not be negative`)
+	}
+	if l.Count > 0 && l.Period <= 0 {
+		return errors.New(name + ` period must be positive`)
+	}
+	return nil
+}
@@ -0,0 +1,224 @@
+package revocation
+
+import (
+	"bytes"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/ocsp"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type Status string
+
+const (
+	StatusGood    Status = `good`
+	StatusRevoked Status = `revoked`
+	StatusUnknown Status = `unknown`
+)
+
+const (
+	SourceOCSP = `ocsp`
+	SourceCRL  = `crl`
+)
+
+// maxResponseBytes limits OCSP responses and CRLs read into memory
+const maxResponseBytes = 16 * 1024 * 1024
+
+type Result struct {
+	Status Status
+	// Source is ocsp or crl, empty if no source answered
+	Source string
+	// URL of responder or CRL distribution point which answered
+	URL       string
+	RevokedAt time.Time
+	Reason    int
+	// Response is raw OCSP response, nil if status came from CRL
+	Response   []byte
+	ThisUpdate time.Time
+	NextUpdate time.Time
+}
+
+type Checker struct {
+	client *http.Client
+	now    func() time.Time
+}
+
+func NewChecker(timeout time.Duration) *Checker {
+	return &Checker{
+		client: &http.Client{Timeout: timeout},
+		now:    time.Now,
+	}
+}
+
+// Check asks OCSP responders of the certificate first and falls back to CRL distribution points.
+// Unknown status is returned with error if no source answered.
+func (c *Checker) Check(certificate *x509.Certificate, issuer *x509.Certificate) (result *Result, err error) {
+	result = &Result{Status: StatusUnknown}
+
+	if certificate == nil || issuer == nil {
+		err = errors.New(`certificate and its issuer are required`)
+		return
+	}
+
+	if len(certificate.OCSPServer) < 1 && len(certificate.CRLDistributionPoints) < 1 {
+		err = errors.New(`certificate has neither OCSP responders nor CRL distribution points`)
+		return
+	}
+
+	failures := make([]string, 0)
+
+	ocspResult, err := c.FetchOCSP(certificate, issuer)
+	if err == nil {
+		return ocspResult, nil
+	}
+	if len(certificate.OCSPServer) > 0 {
+		failures = append(failures, err.Error())
+	}
+
+	for _, url := range certificate.CRLDistributionPoints {
+		var crlResult *Result
+		crlResult, err = c.checkCRL(url, certificate, issuer)
+		if err == nil {
+			return crlResult, nil
+		}
+		failures = append(failures, `crl "`+url+`": `+err.Error())
+	}
+
+	err = errors.New(`revocation status is unknown: ` + strings.Join(failures, `; `))
+
+	return
+}
+
+// FetchOCSP asks OCSP responders of the certificate one by one, first valid response is returned
+func (c *Checker) FetchOCSP(certificate *x509.Certificate, issuer *x509.Certificate) (result *Result, err error) {
+	if len(certificate.OCSPServer) < 1 {
+		err = errors.New(`certificate has no OCSP responders`)
+		return
+	}
+
+	failures := make([]string, 0, len(certificate.OCSPServer))
+	for _, url := range certificate.OCSPServer {
+		result, err = c.checkOCSP(url, certificate, issuer)
+		if err == nil {
+			return
+		}
+		failures = append(failures, `ocsp "`+url+`": `+err.Error())
+	}
+
+	err = errors.New(strings.Join(failures, `; `))
+
+	return
+}
+
+func (c *Checker) checkOCSP(url string, certificate *x509.Certificate, issuer *x509.Certificate) (result *Result, err error) {
+	request, err := ocsp.CreateRequest(certificate, issuer, nil)
+	if err != nil {
+		return
+	}
+
+	body, err := c.fetch(http.MethodPost, url, request)
+	if err != nil {
+		return
+	}
+
+	response, err := ocsp.ParseResponseForCert(body, certificate, issuer)
+	if err != nil {
+		return
+	}
+
+	if !response.NextUpdate.IsZero() && c.now().After(response.NextUpdate) {
+		err = errors.New(`response is outdated`)
+		return
+	}
+
+	result = &Result{
+		Source:     SourceOCSP,
+		URL:        url,
+		Response:   body,
+		ThisUpdate: response.ThisUpdate,
+		NextUpdate: response.NextUpdate,
+	}
+
+	switch response.Status {
+	case ocsp.Good:
+		result.Status = StatusGood
+	case ocsp.Revoked:
+		result.Status = StatusRevoked
+		result.RevokedAt = response.RevokedAt
+		result.Reason = response.RevocationReason
+	default:
+		err = errors.New(`responder does not know the certificate`)
+	}
+
+	return
+}
+
+func (c *Checker) checkCRL(url string, certificate *x509.Certificate, issuer *x509.Certificate) (result *Result, err error) {
+	body, err := c.fetch(http.MethodGet, url, nil)
+	if err != nil {
+		return
+	}
+
+	// ParseCRL is used, as ParseRevocationList requires newer go
+	crl, err := x509.ParseCRL(body)
+	if err != nil {
+		return
+	}
+
+	err = issuer.CheckCRLSignature(crl)
+	if err != nil {
+		return
+	}
+
+	if crl.HasExpired(c.now()) {
+		err = errors.New(`crl is outdated`)
+		return
+	}
+
+	result = &Result{
+		Status:     StatusGood,
+		Source:     SourceCRL,
+		URL:        url,
+		ThisUpdate: crl.TBSCertList.ThisUpdate,
+		NextUpdate: crl.TBSCertList.NextUpdate,
+	}
+
+	for _, revoked := range crl.TBSCertList.RevokedCertificates {
+		if revoked.SerialNumber.Cmp(certificate.SerialNumber) == 0 {
+			result.Status = StatusRevoked
+			result.RevokedAt = revoked.RevocationTime
+			return
+		}
+	}
+
+	return
+}
+
+func (c *Checker) fetch(method string, url string, body []byte) (data []byte, err error) {
+	request, err := http.NewRequest(method, url, bytes.NewReader(body))
+	if err != nil {
+		return
+	}
+	if method == http.MethodPost {
+		request.Header.Set(`Content-Type`, `application/ocsp-request`)
+	}
+
+	response, err := c.client.Do(request)
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = response.Body.Close()
+	}()
+
+	if response.StatusCode != http.StatusOK {
+		err = errors.New(fmt.Sprintf(`server responded with status %d`, response.StatusCode))
+		return
+	}
+
+	return io.ReadAll(io.LimitReader(response.Body, maxResponseBytes))
+}
@@ -0,0 +1,221 @@
+package revocation
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"golang.org/x/crypto/ocsp"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type testAuthority struct {
+	certificate *x509.Certificate
+	key         crypto.Signer
+	server      *httptest.Server
+	// ocspStatus is returned by responder, responder fails if it is negative
+	ocspStatus int
+	revoked    []pkix.RevokedCertificate
+}
+
+func newTestAuthority(t *testing.T) (authority *testAuthority) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: `Test CA`},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certificate, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	authority = &testAuthority{certificate: certificate, key: key, ocspStatus: ocsp.Good}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(`/ocsp`, authority.serveOCSP)
+	mux.HandleFunc(`/crl`, authority.serveCRL)
+	authority.server = httptest.NewServer(mux)
+	t.Cleanup(authority.server.Close)
+
+	return
+}
+
+func (a *testAuthority) serveOCSP(w http.ResponseWriter, r *http.Request) {
+	if a.ocspStatus < 0 {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	body, _ := io.ReadAll(r.Body)
+	request, err := ocsp.ParseRequest(body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	response, err := ocsp.CreateResponse(a.certificate, a.certificate, ocsp.Response{
+		Status:           a.ocspStatus,
+		SerialNumber:     request.SerialNumber,
+		ThisUpdate:       time.Now().Add(-time.Minute),
+		NextUpdate:       time.Now().Add(time.Hour),
+		RevokedAt:        time.Now().Add(-time.Minute),
+		RevocationReason: ocsp.KeyCompromise,
+	}, a.key)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	_, _ = w.Write(response)
+}
+
+func (a *testAuthority) serveCRL(w http.ResponseWriter, r *http.Request) {
+	crl, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+		Number:              big.NewInt(1),
+		ThisUpdate:          time.Now().Add(-time.Minute),
+		NextUpdate:          time.Now().Add(time.Hour),
+		RevokedCertificates: a.revoked,
+	}, a.certificate, a.key)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	_, _ = w.Write(crl)
+}
+
+func (a *testAuthority) issue(t *testing.T, serial int64) *x509.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(serial),
+		Subject:               pkix.Name{CommonName: `example.com`},
+		DNSNames:              []string{`example.com`},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(12 * time.Hour),
+		OCSPServer:            []string{a.server.URL + `/ocsp`},
+		CRLDistributionPoints: []string{a.server.URL + `/crl`},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, a.certificate, key.Public(), a.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certificate, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return certificate
+}
+
+func TestChecker_OCSP(t *testing.T) {
+	authority := newTestAuthority(t)
+	certificate := authority.issue(t, 100)
+	checker := NewChecker(5 * time.Second)
+
+	result, err := checker.Check(certificate, authority.certificate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Status != StatusGood || result.Source != SourceOCSP || len(result.Response) < 1 {
+		t.Fatalf(`expected good status from ocsp, got %s from %s`, result.Status, result.Source)
+	}
+
+	authority.ocspStatus = ocsp.Revoked
+	result, err = checker.Check(certificate, authority.certificate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Status != StatusRevoked || result.Reason != ocsp.KeyCompromise {
+		t.Fatalf(`expected revoked status, got %s`, result.Status)
+	}
+}
+
+func TestChecker_CRLFallback(t *testing.T) {
+	authority := newTestAuthority(t)
+	certificate := authority.issue(t, 200)
+	authority.ocspStatus = -1
+	checker := NewChecker(5 * time.Second)
+
+	result, err := checker.Check(certificate, authority.certificate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Status != StatusGood || result.Source != SourceCRL {
+		t.Fatalf(`expected good status from crl, got %s from %s`, result.Status, result.Source)
+	}
+
+	authority.revoked = []pkix.RevokedCertificate{{SerialNumber: big.NewInt(200), RevocationTime: time.Now().Add(-time.Minute)}}
+	result, err = checker.Check(certificate, authority.certificate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Status != StatusRevoked || result.Source != SourceCRL {
+		t.Fatalf(`expected revoked status from crl, got %s from %s`, result.Status, result.Source)
+	}
+}
+
+func TestChecker_WrongIssuer(t *testing.T) {
+	authority := newTestAuthority(t)
+	other := newTestAuthority(t)
+	certificate := authority.issue(t, 300)
+
+	result, err := NewChecker(5*time.Second).Check(certificate, other.certificate)
+	if err == nil || result.Status != StatusUnknown {
+		t.Fatal(`response signed for another issuer was accepted`)
+	}
+}
+
+func TestGetStapleError(t *testing.T) {
+	authority := newTestAuthority(t)
+	certificate := authority.issue(t, 400)
+
+	result, err := NewChecker(5*time.Second).FetchOCSP(certificate, authority.certificate)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = GetStapleError(result.Response, certificate, authority.certificate, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// responder gives an hour of validity starting a minute ago
+	err = GetStapleError(result.Response, certificate, authority.certificate, time.Now().Add(40*time.Minute))
+	if err == nil {
+		t.Fatal(`staple past half of its validity was accepted`)
+	}
+
+	other := authority.issue(t, 401)
+	err = GetStapleError(result.Response, other, authority.certificate, time.Now())
+	if err == nil {
+		t.Fatal(`staple of another certificate was accepted`)
+	}
+}
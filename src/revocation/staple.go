@@ -0,0 +1,36 @@
+package revocation
+
+import (
+	"crypto/x509"
+	"errors"
+	"golang.org/x/crypto/ocsp"
+	"time"
+)
+
+// GetStapleError returns nil if stored OCSP response belongs to the certificate, is signed by its issuer,
+// says the certificate is good and has not passed half of its validity yet
+func GetStapleError(data []byte, certificate *x509.Certificate, issuer *x509.Certificate, now time.Time) error {
+	if len(data) < 1 {
+		return errors.New(`staple is empty`)
+	}
+
+	response, err := ocsp.ParseResponseForCert(data, certificate, issuer)
+	if err != nil {
+		return err
+	}
+
+	if response.Status != ocsp.Good {
+		return errors.New(`staple does not have good status`)
+	}
+
+	if response.NextUpdate.IsZero() {
+		return errors.New(`staple has no next update time`)
+	}
+
+	halfLife := response.ThisUpdate.Add(response.NextUpdate.Sub(response.ThisUpdate) / 2)
+	if now.After(halfLife) {
+		return errors.New(`staple is past half of its validity`)
+	}
+
+	return nil
+}
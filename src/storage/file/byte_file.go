@@ -37,10 +37,25 @@ func (s *byteFile) Save(data []byte) error {
 	if len(data) < 1 {
 		return s.Delete()
 	}
+	if plan != nil {
+		plan.write(s.filename, data)
+		return nil
+	}
 	return os.WriteFile(s.filename, data, s.permissions)
 }
 
 func (s *byteFile) Load() (bts []byte, err error) {
+	if plan != nil {
+		var planned bool
+		bts, planned = plan.read(s.filename)
+		if planned {
+			if bts == nil {
+				err = storage.EmptyNode
+			}
+			return
+		}
+	}
+
 	bts, err = os.ReadFile(s.filename)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -52,6 +67,10 @@ func (s *byteFile) Load() (bts []byte, err error) {
 }
 
 func (s *byteFile) Delete() error {
+	if plan != nil {
+		plan.remove(s.filename)
+		return nil
+	}
 	err := os.Remove(s.filename)
 	if os.IsNotExist(err) {
 		err = nil
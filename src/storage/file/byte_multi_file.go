@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"ssl/storage"
 	"strconv"
 	"strings"
@@ -170,13 +171,20 @@ func getStorageArrayByPattern(folder string, pattern *regexp.Regexp, permissions
 		return
 	}
 
-	var filename string
-	var store *byteFile
+	filenames := make([]string, 0)
 	for _, file := range files {
 		if file.IsDir() {
 			continue
 		}
-		filename = file.Name()
+		filenames = append(filenames, file.Name())
+	}
+
+	if plan != nil {
+		filenames = mergePlannedFilenames(filenames, plan.filesInFolder(folder))
+	}
+
+	var store *byteFile
+	for _, filename := range filenames {
 		if pattern.MatchString(filename) {
 			store, err = NewByteFile(filepath.Join(folder, filename), permissions)
 			if err != nil {
@@ -188,3 +196,24 @@ func getStorageArrayByPattern(folder string, pattern *regexp.Regexp, permissions
 
 	return
 }
+
+func mergePlannedFilenames(filenames []string, plannedFiles map[string]bool) (merged []string) {
+	merged = make([]string, 0, len(filenames)+len(plannedFiles))
+	for _, filename := range filenames {
+		exists, planned := plannedFiles[filename]
+		if !planned || exists {
+			merged = append(merged, filename)
+		}
+		delete(plannedFiles, filename)
+	}
+
+	for filename, exists := range plannedFiles {
+		if exists {
+			merged = append(merged, filename)
+		}
+	}
+
+	sort.Strings(merged)
+
+	return
+}
@@ -0,0 +1,119 @@
+package file
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+)
+
+type Operation uint8
+
+const (
+	OperationCreate Operation = iota + 1
+	OperationOverwrite
+	OperationDelete
+)
+
+func (o Operation) String() string {
+	switch o {
+	case OperationCreate:
+		return `create`
+	case OperationOverwrite:
+		return `overwrite`
+	case OperationDelete:
+		return `delete`
+	default:
+		return `undefined`
+	}
+}
+
+type PlannedOperation struct {
+	Operation Operation
+	Filename  string
+}
+
+// Plan keeps file changes in memory instead of applying them, so storages see their own writes
+// while the disk stays untouched.
+type Plan struct {
+	files map[string][]byte
+	order []string
+}
+
+var plan *Plan
+
+func NewPlan() *Plan {
+	return &Plan{
+		files: make(map[string][]byte),
+	}
+}
+
+// SetPlan makes all file storages record changes into passed plan. Nil plan restores writing to disk.
+func SetPlan(p *Plan) {
+	plan = p
+}
+
+// Operations compares planned state of every touched file with the disk
+func (p *Plan) Operations() (operations []PlannedOperation) {
+	operations = make([]PlannedOperation, 0)
+	for _, filename := range p.order {
+		data := p.files[filename]
+		current, err := os.ReadFile(filename)
+		existsOnDisk := err == nil
+
+		switch {
+		case data == nil && existsOnDisk:
+			operations = append(operations, PlannedOperation{Operation: OperationDelete, Filename: filename})
+		case data != nil && !existsOnDisk:
+			operations = append(operations, PlannedOperation{Operation: OperationCreate, Filename: filename})
+		case data != nil && !bytes.Equal(data, current):
+			operations = append(operations, PlannedOperation{Operation: OperationOverwrite, Filename: filename})
+		}
+	}
+
+	return
+}
+
+func (p *Plan) write(filename string, data []byte) {
+	filename = filepath.Clean(filename)
+	p.touch(filename)
+	p.files[filename] = make([]byte, len(data))
+	copy(p.files[filename], data)
+}
+
+func (p *Plan) remove(filename string) {
+	filename = filepath.Clean(filename)
+	p.touch(filename)
+	p.files[filename] = nil
+}
+
+// read returns planned content of the file, planned is false if file was not touched yet
+func (p *Plan) read(filename string) (data []byte, planned bool) {
+	plannedData, planned := p.files[filepath.Clean(filename)]
+	if plannedData == nil {
+		return
+	}
+
+	data = make([]byte, len(plannedData))
+	copy(data, plannedData)
+
+	return
+}
+
+// filesInFolder returns touched files of the folder, value shows whether file exists in planned state
+func (p *Plan) filesInFolder(folder string) (files map[string]bool) {
+	folder = filepath.Clean(folder)
+	files = make(map[string]bool)
+	for filename, data := range p.files {
+		if filepath.Dir(filename) == folder {
+			files[filepath.Base(filename)] = data != nil
+		}
+	}
+
+	return
+}
+
+func (p *Plan) touch(filename string) {
+	if _, exists := p.files[filename]; !exists {
+		p.order = append(p.order, filename)
+	}
+}
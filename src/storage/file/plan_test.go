@@ -0,0 +1,142 @@
+package file
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const (
+	testPlanFilenameTmp          = `./test/testplan.tmp`
+	testPlanMultibyteFilename    = `./test/testplan{n}.tmp`
+	testPlanMultibytePermissions = 0666
+	testPlanDeleteFilename       = `./test/testfilesingle.txt`
+)
+
+func TestPlan_SingleFile(t *testing.T) {
+	p := NewPlan()
+	SetPlan(p)
+	defer SetPlan(nil)
+
+	store, err := NewByteFile(testPlanFilenameTmp, testByteFilePermissions)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reference := []byte(`planned data`)
+	err = store.Save(reference)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if fileExists(testPlanFilenameTmp) {
+		t.Fatal(`file was written to disk`)
+	}
+
+	data, err := store.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytesEqual(data, reference) {
+		t.Fatal(`planned data does not match`)
+	}
+
+	assertOperations(t, p.Operations(), PlannedOperation{Operation: OperationCreate, Filename: filepath.Clean(testPlanFilenameTmp)})
+}
+
+func TestPlan_OverwriteAndDelete(t *testing.T) {
+	p := NewPlan()
+	SetPlan(p)
+	defer SetPlan(nil)
+
+	overwriteStore, err := NewByteFile(testByteFilename, testByteFilePermissions)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = overwriteStore.Save([]byte(`new data`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deleteStore, err := NewByteFile(testPlanDeleteFilename, testByteFilePermissions)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = deleteStore.Delete()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !fileExists(testPlanDeleteFilename) {
+		t.Fatal(`file was deleted from disk`)
+	}
+
+	assertOperations(
+		t,
+		p.Operations(),
+		PlannedOperation{Operation: OperationOverwrite, Filename: filepath.Clean(testByteFilename)},
+		PlannedOperation{Operation: OperationDelete, Filename: filepath.Clean(testPlanDeleteFilename)},
+	)
+}
+
+func TestPlan_MultiFileStale(t *testing.T) {
+	multiStore, err := NewByteMultiFile(testPlanMultibyteFilename, testPlanMultibytePermissions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = multiStore.Delete()
+	}()
+
+	err = multiStore.Save(tmpMultiReference)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := NewPlan()
+	SetPlan(p)
+	defer SetPlan(nil)
+
+	reference := [][]byte{[]byte(`single intermediate`)}
+	err = multiStore.Save(reference)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := multiStore.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytesArrEqual(data, reference) {
+		t.Fatal(`planned data does not match`)
+	}
+
+	assertOperations(
+		t,
+		p.Operations(),
+		PlannedOperation{Operation: OperationOverwrite, Filename: filepath.Clean(`./test/testplan1.tmp`)},
+		PlannedOperation{Operation: OperationDelete, Filename: filepath.Clean(`./test/testplan2.tmp`)},
+		PlannedOperation{Operation: OperationDelete, Filename: filepath.Clean(`./test/testplan3.tmp`)},
+	)
+
+	_, err = os.Stat(`./test/testplan3.tmp`)
+	if err != nil {
+		t.Fatal(`file was deleted from disk`)
+	}
+}
+
+func assertOperations(t *testing.T, operations []PlannedOperation, reference ...PlannedOperation) {
+	t.Helper()
+	if len(operations) != len(reference) {
+		t.Fatalf(`%d operations planned, but %d expected: %v`, len(operations), len(reference), operations)
+	}
+	for i := range reference {
+		if operations[i] != reference[i] {
+			t.Fatalf(`operation %v planned, but %v expected`, operations[i], reference[i])
+		}
+	}
+}
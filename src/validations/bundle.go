@@ -3,45 +3,44 @@ package validations
 import (
 	"crypto/rsa"
 	"crypto/x509"
-	"time"
 )
 
 func GetCertificateBundleValidationError(
 	certKey *rsa.PrivateKey,
 	certificateChain []*x509.Certificate,
 	domains []string,
-	minLeftTime time.Duration,
+	threshold RenewalThreshold,
 ) (err error) {
 	err = GetBasicCertificateChainError(certificateChain)
 	if err != nil {
-		return
+		return wrapError(ReasonChain, err)
 	}
 
 	err = GetCertificatesOrderError(certificateChain)
 	if err != nil {
-		return
+		return wrapError(ReasonOrder, err)
 	}
 
-	err = GetCertificatesExpireError(certificateChain, minLeftTime)
+	err = GetCertificatesExpireError(certificateChain, threshold)
 	if err != nil {
-		return
+		return wrapError(ReasonExpire, err)
 	}
 
 	err = GetBasicRSAPrivateKeyError(certKey)
 	if err != nil {
-		return
+		return wrapError(ReasonKey, err)
 	}
 
 	certificate := certificateChain[0]
 
 	err = GetPrivateKeyMatchCertificateError(certificate, certKey)
 	if err != nil {
-		return
+		return wrapError(ReasonKeyMismatch, err)
 	}
 
 	err = GetDomainMatchError(certificate, domains)
 	if err != nil {
-		return
+		return wrapError(ReasonDomainsMatch, err)
 	}
 
 	return
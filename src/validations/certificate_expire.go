@@ -9,9 +9,33 @@ import (
 
 const timeFormat = `2006-01-02 15:04:05 MST`
 
-func GetCertificatesExpireError(certificateChain []*x509.Certificate, minLeftTime time.Duration) (err error) {
+// RenewalThreshold tells how long before expiration certificate has to be renewed.
+// Fraction of the leaf validity period is used if set, fixed duration otherwise.
+type RenewalThreshold struct {
+	Before time.Duration
+	// At is elapsed part of validity period after which certificate is renewed, e.g. 0.66
+	At float64
+}
+
+// GetMinLeftTime returns time certificate must still be valid for
+func (t RenewalThreshold) GetMinLeftTime(certificate *x509.Certificate) time.Duration {
+	if t.At <= 0 || certificate == nil {
+		return t.Before
+	}
+
+	lifetime := certificate.NotAfter.Sub(certificate.NotBefore)
+
+	return time.Duration(float64(lifetime) * (1 - t.At))
+}
+
+// GetCertificatesExpireError checks every certificate of the chain against time left computed for the leaf
+func GetCertificatesExpireError(certificateChain []*x509.Certificate, threshold RenewalThreshold) (err error) {
+	if len(certificateChain) < 1 {
+		return
+	}
+
 	curDate := time.Now()
-	expireDate := curDate.Add(minLeftTime)
+	expireDate := curDate.Add(threshold.GetMinLeftTime(certificateChain[0]))
 
 	for _, cert := range certificateChain {
 		if curDate.Before(cert.NotBefore) {
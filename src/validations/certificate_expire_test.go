@@ -0,0 +1,81 @@
+package validations
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func generateTestCertificate(t *testing.T, notBefore time.Time, notAfter time.Time) *x509.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: `example.com`},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certificate, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return certificate
+}
+
+func TestGetCertificatesExpireError_Fraction(t *testing.T) {
+	now := time.Now()
+
+	// 6 day certificate issued 3 days ago, half of validity has passed
+	certificate := generateTestCertificate(t, now.Add(-72*time.Hour), now.Add(72*time.Hour))
+
+	err := GetCertificatesExpireError([]*x509.Certificate{certificate}, RenewalThreshold{At: 0.66})
+	if err != nil {
+		t.Fatal(`certificate was renewed before threshold: ` + err.Error())
+	}
+
+	err = GetCertificatesExpireError([]*x509.Certificate{certificate}, RenewalThreshold{At: 0.4})
+	if err == nil {
+		t.Fatal(`certificate past threshold was accepted`)
+	}
+}
+
+func TestGetCertificatesExpireError_Duration(t *testing.T) {
+	now := time.Now()
+	certificate := generateTestCertificate(t, now.Add(-24*time.Hour), now.Add(48*time.Hour))
+
+	err := GetCertificatesExpireError([]*x509.Certificate{certificate}, RenewalThreshold{Before: 36 * time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = GetCertificatesExpireError([]*x509.Certificate{certificate}, RenewalThreshold{Before: 60 * time.Hour})
+	if err == nil {
+		t.Fatal(`certificate expiring within threshold was accepted`)
+	}
+}
+
+func TestRenewalThreshold_IntermediateCheckedAgainstLeaf(t *testing.T) {
+	now := time.Now()
+	leaf := generateTestCertificate(t, now.Add(-24*time.Hour), now.Add(30*24*time.Hour))
+	intermediate := generateTestCertificate(t, now.Add(-365*24*time.Hour), now.Add(5*24*time.Hour))
+
+	err := GetCertificatesExpireError([]*x509.Certificate{leaf, intermediate}, RenewalThreshold{At: 0.5})
+	if err == nil {
+		t.Fatal(`intermediate expiring before leaf renewal point was accepted`)
+	}
+}
@@ -0,0 +1,43 @@
+package validations
+
+import "errors"
+
+const (
+	ReasonUnknown      = `unknown`
+	ReasonChain        = `chain`
+	ReasonOrder        = `order`
+	ReasonExpire       = `expire`
+	ReasonKey          = `key`
+	ReasonKeyMismatch  = `key_mismatch`
+	ReasonDomainsMatch = `domains`
+	ReasonRevoked      = `revoked`
+)
+
+// Error keeps reason of failed validation, so failures can be counted by kind
+type Error struct {
+	Reason string
+	Err    error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func GetErrorReason(err error) string {
+	var validationError *Error
+	if errors.As(err, &validationError) {
+		return validationError.Reason
+	}
+	return ReasonUnknown
+}
+
+func wrapError(reason string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Reason: reason, Err: err}
+}
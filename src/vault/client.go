@@ -0,0 +1,75 @@
+package vault
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// maxResponseBytes limits Vault responses read into memory
+const maxResponseBytes = 1024 * 1024
+
+type response struct {
+	Data   json.RawMessage `json:"data"`
+	Auth   *authResponse   `json:"auth"`
+	Errors []string        `json:"errors"`
+}
+
+type authResponse struct {
+	ClientToken string `json:"client_token"`
+}
+
+// post sends JSON request to Vault API and decodes data or auth part of the response
+func (i *Issuer) post(path string, token string, request interface{}) (result *response, err error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return
+	}
+
+	httpRequest, err := http.NewRequest(http.MethodPost, i.settings.Address+`/v1/`+strings.TrimPrefix(path, `/`), bytes.NewReader(body))
+	if err != nil {
+		return
+	}
+	httpRequest.Header.Set(`Content-Type`, `application/json`)
+	if token != `` {
+		httpRequest.Header.Set(`X-Vault-Token`, token)
+	}
+	if i.settings.Namespace != `` {
+		httpRequest.Header.Set(`X-Vault-Namespace`, i.settings.Namespace)
+	}
+
+	httpResponse, err := i.client.Do(httpRequest)
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = httpResponse.Body.Close()
+	}()
+
+	data, err := io.ReadAll(io.LimitReader(httpResponse.Body, maxResponseBytes))
+	if err != nil {
+		return
+	}
+
+	result = &response{}
+	if len(data) > 0 {
+		err = json.Unmarshal(data, result)
+		if err != nil && httpResponse.StatusCode < 300 {
+			return nil, errors.New(`vault returned invalid response: ` + err.Error())
+		}
+	}
+
+	if httpResponse.StatusCode < 200 || httpResponse.StatusCode > 299 {
+		message := strings.Join(result.Errors, `; `)
+		if message == `` {
+			message = http.StatusText(httpResponse.StatusCode)
+		}
+		return nil, errors.New(fmt.Sprintf(`vault POST %s returned %d: %s`, path, httpResponse.StatusCode, message))
+	}
+
+	return result, nil
+}
@@ -0,0 +1,228 @@
+package vault
+
+import (
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"ssl/converters"
+	"ssl/issuer"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Settings struct {
+	// Address is Vault URL, e.g. "https://vault.internal:8200"
+	Address   string
+	Namespace string
+	// Mount is path of PKI secrets engine
+	Mount string
+	Role  string
+	// TTL of issued certificates, role default is used if zero
+	TTL time.Duration
+	// AltNames and IPSANs are requested in addition to identifiers
+	AltNames []string
+	IPSANs   []string
+	// Token is used if set, AppRole login is performed otherwise
+	Token        string
+	AppRoleMount string
+	RoleID       string
+	SecretID     string
+	Timeout      time.Duration
+}
+
+// Issuer signs CSRs with Vault PKI secrets engine
+type Issuer struct {
+	settings Settings
+	client   *http.Client
+	mutex    sync.Mutex
+	token    string
+}
+
+type signRequest struct {
+	CSR        string `json:"csr"`
+	CommonName string `json:"common_name"`
+	AltNames   string `json:"alt_names,omitempty"`
+	IPSANs     string `json:"ip_sans,omitempty"`
+	TTL        string `json:"ttl,omitempty"`
+	Format     string `json:"format"`
+}
+
+type signResponse struct {
+	Certificate string   `json:"certificate"`
+	IssuingCA   string   `json:"issuing_ca"`
+	CAChain     []string `json:"ca_chain"`
+}
+
+func NewIssuer(settings Settings) (*Issuer, error) {
+	if settings.Address == `` || settings.Mount == `` || settings.Role == `` {
+		return nil, errors.New(`vault address, mount and role are required`)
+	}
+	if settings.Token == `` && (settings.RoleID == `` || settings.SecretID == ``) {
+		return nil, errors.New(`vault token or AppRole role_id and secret_id are required`)
+	}
+
+	settings.Address = strings.TrimSuffix(settings.Address, `/`)
+
+	return &Issuer{
+		settings: settings,
+		client:   &http.Client{Timeout: settings.Timeout},
+		token:    settings.Token,
+	}, nil
+}
+
+func (i *Issuer) Name() string {
+	return `vault`
+}
+
+// getToken logs in with AppRole once, static token is returned as is
+func (i *Issuer) getToken() (token string, err error) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	if i.token != `` {
+		return i.token, nil
+	}
+
+	result, err := i.post(`auth/`+i.settings.AppRoleMount+`/login`, ``, map[string]string{
+		`role_id`:   i.settings.RoleID,
+		`secret_id`: i.settings.SecretID,
+	})
+	if err != nil {
+		return
+	}
+	if result.Auth == nil || result.Auth.ClientToken == `` {
+		return ``, errors.New(`vault AppRole login returned no token`)
+	}
+
+	i.token = result.Auth.ClientToken
+
+	return i.token, nil
+}
+
+func (i *Issuer) Obtain(request issuer.Request) (certificates []*x509.Certificate, err error) {
+	err = request.Validate()
+	if err != nil {
+		return
+	}
+
+	csr := request.CSR
+	if csr == nil {
+		csr, err = createCSR(request)
+		if err != nil {
+			return
+		}
+	}
+
+	token, err := i.getToken()
+	if err != nil {
+		return
+	}
+
+	body := i.getSignRequest(request.Identifiers, csr)
+	result, err := i.post(i.settings.Mount+`/sign/`+i.settings.Role, token, body)
+	if err != nil {
+		return
+	}
+
+	signed := &signResponse{}
+	err = json.Unmarshal(result.Data, signed)
+	if err != nil {
+		return nil, errors.New(`vault returned invalid certificate data: ` + err.Error())
+	}
+
+	chain := append([]string{signed.Certificate}, signed.CAChain...)
+	if len(signed.CAChain) < 1 && signed.IssuingCA != `` {
+		chain = append(chain, signed.IssuingCA)
+	}
+
+	_, certificates, err = converters.DecodeKeysAndCertificates([]byte(strings.Join(chain, "\n")), ``)
+	if err == nil && len(certificates) < 1 {
+		err = errors.New(`vault returned no certificates`)
+	}
+
+	return
+}
+
+// getSignRequest puts the first DNS identifier into common name, the rest goes to alt names or IP SANs
+func (i *Issuer) getSignRequest(identifiers []string, csr *x509.CertificateRequest) *signRequest {
+	altNames := make([]string, 0)
+	ipSANs := make([]string, 0)
+	for _, identifier := range append(append([]string{}, identifiers...), i.settings.AltNames...) {
+		if net.ParseIP(identifier) != nil {
+			ipSANs = append(ipSANs, identifier)
+		} else {
+			altNames = append(altNames, identifier)
+		}
+	}
+	ipSANs = append(ipSANs, i.settings.IPSANs...)
+
+	body := &signRequest{
+		CSR:      string(pem.EncodeToMemory(&pem.Block{Type: `CERTIFICATE REQUEST`, Bytes: csr.Raw})),
+		AltNames: strings.Join(altNames, `,`),
+		IPSANs:   strings.Join(ipSANs, `,`),
+		Format:   `pem`,
+	}
+	if len(altNames) > 0 {
+		body.CommonName = altNames[0]
+	} else if len(ipSANs) > 0 {
+		body.CommonName = ipSANs[0]
+	}
+	if i.settings.TTL > 0 {
+		body.TTL = i.settings.TTL.String()
+	}
+
+	return body
+}
+
+func (i *Issuer) Revoke(certificate *x509.Certificate, reason int) (err error) {
+	token, err := i.getToken()
+	if err != nil {
+		return
+	}
+
+	_, err = i.post(i.settings.Mount+`/revoke`, token, map[string]string{
+		`serial_number`: formatSerial(certificate),
+	})
+
+	return
+}
+
+// RenewalInfo is not supported, Vault does not suggest renewal windows
+func (i *Issuer) RenewalInfo(certificate *x509.Certificate, issuerCertificate *x509.Certificate) (*issuer.RenewalInfo, error) {
+	return nil, issuer.ErrNotSupported
+}
+
+func createCSR(request issuer.Request) (csr *x509.CertificateRequest, err error) {
+	template := &x509.CertificateRequest{Subject: pkix.Name{CommonName: request.Identifiers[0]}}
+	for _, identifier := range request.Identifiers {
+		if ip := net.ParseIP(identifier); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, identifier)
+		}
+	}
+
+	raw, err := x509.CreateCertificateRequest(rand.Reader, template, request.Key)
+	if err != nil {
+		return
+	}
+
+	return x509.ParseCertificateRequest(raw)
+}
+
+// formatSerial gives Vault serial format, e.g. "3e:8a:01"
+func formatSerial(certificate *x509.Certificate) string {
+	serial := certificate.SerialNumber.Bytes()
+	parts := make([]string, 0, len(serial))
+	for _, octet := range serial {
+		parts = append(parts, fmt.Sprintf(`%02x`, octet))
+	}
+	return strings.Join(parts, `:`)
+}
@@ -0,0 +1,181 @@
+package vault
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"ssl/issuer"
+	"ssl/localca"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	testToken    = `s.test-token`
+	testRoleID   = `role-id`
+	testSecretID = `secret-id`
+)
+
+// fakeVault implements AppRole login, PKI sign and revoke endpoints
+type fakeVault struct {
+	ca          *localca.CA
+	signed      map[string]string
+	revoked     []string
+	logins      int
+	namespace   string
+	lastRequest map[string]string
+}
+
+func generateKey(t *testing.T) *rsa.PrivateKey {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func startFakeVault(t *testing.T) (*fakeVault, string) {
+	ca, err := localca.New(generateKey(t), `vault CA`, 24*time.Hour, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	vault := &fakeVault{ca: ca}
+
+	server := httptest.NewServer(http.HandlerFunc(vault.handle))
+	t.Cleanup(server.Close)
+
+	return vault, server.URL
+}
+
+func (v *fakeVault) handle(w http.ResponseWriter, r *http.Request) {
+	body := make(map[string]string)
+	_ = json.NewDecoder(r.Body).Decode(&body)
+	v.lastRequest = body
+	v.namespace = r.Header.Get(`X-Vault-Namespace`)
+
+	if r.URL.Path == `/v1/auth/approle/login` {
+		if body[`role_id`] != testRoleID || body[`secret_id`] != testSecretID {
+			writeResponse(w, http.StatusBadRequest, map[string]interface{}{`errors`: []string{`invalid role or secret ID`}})
+			return
+		}
+		v.logins++
+		writeResponse(w, http.StatusOK, map[string]interface{}{`auth`: map[string]string{`client_token`: testToken}})
+		return
+	}
+
+	if r.Header.Get(`X-Vault-Token`) != testToken {
+		writeResponse(w, http.StatusForbidden, map[string]interface{}{`errors`: []string{`permission denied`}})
+		return
+	}
+
+	switch r.URL.Path {
+	case `/v1/pki_int/sign/web`:
+		block, _ := pem.Decode([]byte(body[`csr`]))
+		csr, err := x509.ParseCertificateRequest(block.Bytes)
+		if err != nil {
+			writeResponse(w, http.StatusBadRequest, map[string]interface{}{`errors`: []string{err.Error()}})
+			return
+		}
+		names := strings.Split(body[`alt_names`], `,`)
+		certificate, err := v.ca.Issue(csr.PublicKey, append(names, strings.Split(body[`ip_sans`], `,`)...), time.Hour, time.Now())
+		if err != nil {
+			writeResponse(w, http.StatusBadRequest, map[string]interface{}{`errors`: []string{err.Error()}})
+			return
+		}
+		writeResponse(w, http.StatusOK, map[string]interface{}{`data`: map[string]interface{}{
+			`certificate`: string(pem.EncodeToMemory(&pem.Block{Type: `CERTIFICATE`, Bytes: certificate.Raw})),
+			`issuing_ca`:  string(pem.EncodeToMemory(&pem.Block{Type: `CERTIFICATE`, Bytes: v.ca.Certificate.Raw})),
+		}})
+	case `/v1/pki_int/revoke`:
+		v.revoked = append(v.revoked, body[`serial_number`])
+		writeResponse(w, http.StatusOK, map[string]interface{}{`data`: map[string]interface{}{`revocation_time`: time.Now().Unix()}})
+	default:
+		writeResponse(w, http.StatusNotFound, map[string]interface{}{`errors`: []string{}})
+	}
+}
+
+func writeResponse(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set(`Content-Type`, `application/json`)
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func TestObtainWithAppRole(t *testing.T) {
+	vault, address := startFakeVault(t)
+
+	vaultIssuer, err := NewIssuer(Settings{
+		Address:      address + `/`,
+		Namespace:    `team`,
+		Mount:        `pki_int`,
+		Role:         `web`,
+		TTL:          time.Hour,
+		IPSANs:       []string{`10.0.0.1`},
+		AppRoleMount: `approle`,
+		RoleID:       testRoleID,
+		SecretID:     testSecretID,
+		Timeout:      time.Second,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key := generateKey(t)
+	certificates, err := vaultIssuer.Obtain(issuer.Request{Identifiers: []string{`api.internal`, `www.api.internal`}, Key: key})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if vault.namespace != `team` || vault.lastRequest[`common_name`] != `api.internal` || vault.lastRequest[`ttl`] != `1h0m0s` {
+		t.Fatal(`unexpected sign request: `, vault.lastRequest, vault.namespace)
+	}
+	if len(certificates) != 2 || !certificates[1].Equal(vault.ca.Certificate) {
+		t.Fatal(`leaf followed by issuing CA expected`)
+	}
+	if !key.PublicKey.Equal(certificates[0].PublicKey) {
+		t.Fatal(`certificate is signed for another key`)
+	}
+	if len(certificates[0].IPAddresses) != 1 || certificates[0].VerifyHostname(`www.api.internal`) != nil {
+		t.Fatal(`alt names and IP SANs should be requested`)
+	}
+
+	err = vaultIssuer.Revoke(certificates[0], 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vault.revoked) != 1 {
+		t.Fatal(`one revocation expected`)
+	}
+	serial, ok := new(big.Int).SetString(strings.ReplaceAll(vault.revoked[0], `:`, ``), 16)
+	if !ok || serial.Cmp(certificates[0].SerialNumber) != 0 || !strings.Contains(vault.revoked[0], `:`) {
+		t.Fatal(`certificate should be revoked by colon separated serial, got: ` + vault.revoked[0])
+	}
+
+	if vault.logins != 1 {
+		t.Fatal(`AppRole login should be performed once`)
+	}
+}
+
+func TestObtainErrors(t *testing.T) {
+	_, address := startFakeVault(t)
+
+	vaultIssuer, err := NewIssuer(Settings{Address: address, Mount: `pki_int`, Role: `web`, Token: `wrong`, Timeout: time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = vaultIssuer.Obtain(issuer.Request{Identifiers: []string{`api.internal`}, Key: generateKey(t)})
+	if err == nil || !strings.Contains(err.Error(), `403: permission denied`) {
+		t.Fatal(`vault error should be reported, got: `, err)
+	}
+
+	_, err = NewIssuer(Settings{Address: address, Mount: `pki_int`, Role: `web`})
+	if err == nil {
+		t.Fatal(`settings without credentials should be rejected`)
+	}
+}
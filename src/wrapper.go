@@ -2,27 +2,77 @@ package main
 
 import (
 	"errors"
+	"flag"
+	"os"
+	"ssl/config"
+	"ssl/endpoint"
+	"ssl/hooks"
+	loglib "ssl/logger"
+	"ssl/preflight"
+	"ssl/ratelimit"
 )
 
 var NoChangeError = errors.New(`command executed successfully but nothing changed`)
 
-func wrapper() ExitCode {
+func wrapper(args []string) ExitCode {
+	inv, err := parseCommandLine(args)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return OK
+		}
+		logger.Error(err)
+		return ERROR
+	}
+
+	if inv.command.printsResult {
+		loglib.SetInfoOutput(os.Stderr)
+	}
+
 	logger.Infof(`starting application...`)
 	defer logger.Infof(`exited`)
 
-	appConfig, err := getConfig(`APP_ENV`, `APP_CONFIG_FOLDER`)
+	reloadConfig = func() (config.ConfigInterface, error) {
+		return getConfig(`APP_ENV`, `APP_CONFIG_FOLDER`, inv.overrides)
+	}
+
+	appConfig, err := reloadConfig()
 	if err != nil {
 		logger.Error(err)
 		return ERROR
 	}
 
-	err = app(appConfig)
+	err = inv.command.run(appConfig, inv.args)
 	if err != nil {
 		if err != NoChangeError {
 			logger.Error(err)
-			return ERROR
+			return getErrorExitCode(err)
 		}
 		return NO_CHANGE
 	}
 	return OK
 }
+
+// getErrorExitCode reports failed hooks separately, so callers can tell them from failed renewals
+func getErrorExitCode(err error) ExitCode {
+	var hookErr *hooks.Error
+	if errors.As(err, &hookErr) {
+		return HOOK_ERROR
+	}
+
+	var preflightErr *preflight.Error
+	if errors.As(err, &preflightErr) {
+		return PREFLIGHT_ERROR
+	}
+
+	var limitErr *ratelimit.Error
+	if errors.As(err, &limitErr) {
+		return RATE_LIMITED
+	}
+
+	var endpointErr *endpoint.Error
+	if errors.As(err, &endpointErr) {
+		return VERIFY_ERROR
+	}
+
+	return ERROR
+}